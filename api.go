@@ -66,6 +66,12 @@ func newConn(ctx context.Context, module api.Module) (_ *Conn, err error) {
 			lastRowid:     getFun("sqlite3_last_insert_rowid"),
 			changes:       getFun("sqlite3_changes64"),
 			interrupt:     getFun("sqlite3_interrupt"),
+
+			// Optional: nil if the SQLite binary is too old,
+			// or doesn't export them.
+			stmtExplain:   module.ExportedFunction("sqlite3_stmt_explain"),
+			stmtIsExplain: module.ExportedFunction("sqlite3_stmt_isexplain"),
+			totalChanges:  module.ExportedFunction("sqlite3_total_changes64"),
 		},
 	}
 	if err != nil {
@@ -111,4 +117,7 @@ type sqliteAPI struct {
 	lastRowid     api.Function
 	changes       api.Function
 	interrupt     api.Function
+	stmtExplain   api.Function
+	stmtIsExplain api.Function
+	totalChanges  api.Function
 }
@@ -0,0 +1,108 @@
+package sqlite3
+
+// stmtCache holds prepared statements for reuse by
+// [Conn.ExecParams] and [Conn.Query], keyed by their SQL.
+// The least recently used statement is evicted first.
+type stmtCache struct {
+	size  int
+	stmts []cachedStmt // least recently used first
+}
+
+type cachedStmt struct {
+	sql  string
+	tail string
+	stmt *Stmt
+}
+
+// SetStatementCacheSize sets how many prepared statements
+// the connection keeps for reuse by [Conn.ExecParams] and [Conn.Query],
+// so running the same SQL again doesn't prepare it again.
+// Statements are cached by their SQL text, so SQL with different
+// literal values is cached separately: use parameters instead.
+// A size of zero (the default) disables the cache.
+//
+// Statements evicted from the cache are finalized.
+// Cached statements are finalized when the connection is closed.
+func (c *Conn) SetStatementCacheSize(n int) {
+	if n < 0 {
+		n = 0
+	}
+
+	locked := c.lock()
+	c.stmtCache.size = n
+	evict := c.stmtCache.trim()
+	c.unlock(locked)
+
+	for _, e := range evict {
+		e.stmt.Close()
+	}
+}
+
+// prepareCached takes a statement for sql from the cache,
+// or prepares a new one.
+// The statement must be returned with [Conn.releaseCached].
+func (c *Conn) prepareCached(sql string) (stmt *Stmt, tail string, err error) {
+	locked := c.lock()
+	for i := len(c.stmtCache.stmts) - 1; i >= 0; i-- {
+		if e := c.stmtCache.stmts[i]; e.sql == sql {
+			// Remove it while in use, so it's not handed out twice.
+			c.stmtCache.stmts = append(c.stmtCache.stmts[:i], c.stmtCache.stmts[i+1:]...)
+			c.unlock(locked)
+			return e.stmt, e.tail, nil
+		}
+	}
+	c.unlock(locked)
+	return c.Prepare(sql)
+}
+
+// releaseCached resets a statement from [Conn.prepareCached],
+// and returns it to the cache, or finalizes it if the cache is disabled.
+// If finalizing fails, and *errp is nil, the error is stored in *errp.
+func (c *Conn) releaseCached(sql, tail string, stmt *Stmt, errp *error) {
+	locked := c.lock()
+	size := c.stmtCache.size
+	c.unlock(locked)
+
+	if size == 0 {
+		if err := stmt.Close(); *errp == nil {
+			*errp = err
+		}
+		return
+	}
+
+	// Errors from the last step were already reported.
+	stmt.Reset()
+	if err := stmt.ClearBindings(); err != nil {
+		stmt.Close()
+		return
+	}
+
+	locked = c.lock()
+	c.stmtCache.stmts = append(c.stmtCache.stmts, cachedStmt{sql, tail, stmt})
+	evict := c.stmtCache.trim()
+	c.unlock(locked)
+
+	for _, e := range evict {
+		e.stmt.Close()
+	}
+}
+
+// trim removes and returns the least recently used statements
+// over the cache size.
+func (s *stmtCache) trim() []cachedStmt {
+	n := len(s.stmts) - s.size
+	if n <= 0 {
+		return nil
+	}
+	evict := append([]cachedStmt(nil), s.stmts[:n]...)
+	s.stmts = append(s.stmts[:0], s.stmts[n:]...)
+	return evict
+}
+
+// clear removes and finalizes all cached statements.
+func (s *stmtCache) clear() {
+	for _, e := range s.stmts {
+		e.stmt.Close()
+	}
+	s.stmts = nil
+}
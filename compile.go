@@ -2,7 +2,8 @@ package sqlite3
 
 import (
 	"context"
-	"crypto/rand"
+	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"strconv"
@@ -15,31 +16,62 @@ import (
 
 // Configure SQLite.
 var (
-	Binary []byte // Binary to load.
-	Path   string // Path to load the binary from.
+	Binary  []byte // Binary to load.
+	Path    string // Path to load the binary from.
+	TempDir string // Directory for temporary files (default: [os.TempDir]).
+
+	// Source of randomness for connections opened after it's set
+	// (default: [crypto/rand.Reader]).
+	// Use a deterministic reader to make random() and randomblob() reproducible.
+	// If connections are used concurrently, it must be safe for concurrent use.
+	RandSource io.Reader
 )
 
 var sqlite3 sqlite3Runtime
 
 type sqlite3Runtime struct {
 	once      sync.Once
+	done      chan struct{}
 	runtime   wazero.Runtime
 	compiled  wazero.CompiledModule
 	instances atomic.Uint64
 	err       error
 }
 
+// compile compiles the module once, in the background,
+// waiting for it to finish or for ctx to be done.
+// Giving up on a wait doesn't cancel compilation, which is shared.
+func (s *sqlite3Runtime) compile(ctx context.Context) error {
+	s.once.Do(func() {
+		s.done = make(chan struct{})
+		go func() {
+			defer close(s.done)
+			s.compileModule(context.Background())
+		}()
+	})
+
+	select {
+	case <-s.done:
+	default:
+		select {
+		case <-s.done:
+		case <-ctx.Done():
+			return fmt.Errorf("sqlite3: compiling module: %w", ctx.Err())
+		}
+	}
+	return s.err
+}
+
 func (s *sqlite3Runtime) instantiateModule(ctx context.Context) (api.Module, error) {
-	s.once.Do(func() { s.compileModule(ctx) })
-	if s.err != nil {
-		return nil, s.err
+	if err := s.compile(ctx); err != nil {
+		return nil, err
 	}
 
 	cfg := wazero.NewModuleConfig().
 		WithName("sqlite3-" + strconv.FormatUint(s.instances.Add(1), 10)).
 		WithSysWalltime().WithSysNanotime().WithSysNanosleep().
 		WithOsyield(runtime.Gosched).
-		WithRandSource(rand.Reader)
+		WithRandSource(vfsRandSource(ctx))
 	return s.runtime.InstantiateModule(ctx, s.compiled, cfg)
 }
 
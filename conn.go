@@ -3,9 +3,16 @@ package sqlite3
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"math"
+	"net/url"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/ncruces/go-sqlite3/internal/util"
 )
 
 // Conn is a database connection handle.
@@ -22,6 +29,15 @@ type Conn struct {
 	interrupt context.Context
 	waiter    chan struct{}
 	pending   *Stmt
+
+	baseChanges uint64
+	timeFormat  TimeFormat
+	serial      sync.Mutex
+	serialized  bool
+	primaryOnly bool
+	lastErr     *Error
+	clientData  map[string]any
+	stmtCache   stmtCache
 }
 
 // Open calls [OpenFlags] with [OPEN_READWRITE] and [OPEN_CREATE].
@@ -31,9 +47,33 @@ func Open(filename string) (conn *Conn, err error) {
 
 // OpenFlags opens an SQLite database file as specified by the filename argument.
 //
+// [OPEN_EXRESCODE] is always set, see [Conn.ExtendedResultCodes].
+//
 // https://www.sqlite.org/c3ref/open.html
 func OpenFlags(filename string, flags OpenFlag) (conn *Conn, err error) {
-	ctx := context.Background()
+	return OpenContext(context.Background(), filename, flags)
+}
+
+// OpenContext is like [OpenFlags], but gives up waiting for the SQLite binary
+// to be compiled, which happens on first use, when ctx is done.
+// The returned error then wraps the context's error.
+// Compilation continues in the background, for later calls to use.
+//
+// The context is not otherwise associated with the connection,
+// see [Conn.SetInterrupt] for that.
+//
+// https://www.sqlite.org/c3ref/open.html
+func OpenContext(ctx context.Context, filename string, flags OpenFlag) (conn *Conn, err error) {
+	if err := sqlite3.compile(ctx); err != nil {
+		return nil, err
+	}
+
+	flags |= OPEN_EXRESCODE
+
+	ctx = context.Background()
+	if RandSource != nil {
+		ctx = context.WithValue(ctx, randSourceKey{}, RandSource)
+	}
 	module, err := sqlite3.instantiateModule(ctx)
 	if err != nil {
 		return nil, err
@@ -66,6 +106,56 @@ func OpenFlags(filename string, flags OpenFlag) (conn *Conn, err error) {
 	return c, nil
 }
 
+// OpenImmutable opens the SQLite database file at path, read-only,
+// for databases on read-only media, where locking fails.
+//
+// The database is opened as immutable: SQLite does no locking
+// and does not check for changes, and any attempt to write
+// to the database fails with [READONLY].
+// If the file is changed while the connection is open,
+// for instance by another process, reads may return incorrect results,
+// or fail with [CORRUPT].
+//
+// https://www.sqlite.org/uri.html#uriimmutable
+func OpenImmutable(path string) (*Conn, error) {
+	return OpenFlags(immutableURI(path), OPEN_READONLY|OPEN_URI)
+}
+
+// OpenFS opens the SQLite database file name from fsys, read-only.
+//
+// The file must implement [io.ReaderAt],
+// as files from [embed.FS] and [os.DirFS] do, or [io.Seeker],
+// in which case reads seek the file, and are serialized.
+// The database is opened as immutable: SQLite does no locking
+// and does not check for changes, and any attempt to write
+// to the database fails with [READONLY].
+//
+// https://www.sqlite.org/uri.html#uriimmutable
+func OpenFS(fsys fs.FS, name string) (*Conn, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	// SQLite opens the database file before OpenFlags returns,
+	// so the name needs to be registered only for the duration of the call.
+	path, unregister := vfsRegisterFS(fsys, name)
+	defer unregister()
+
+	return OpenFlags(immutableURI(path), OPEN_READONLY|OPEN_URI)
+}
+
+// immutableURI returns a file: URI that opens path as immutable.
+// The URI has no authority, so a relative path stays relative:
+// file://dir/name would name host dir.
+func immutableURI(path string) string {
+	path = filepath.ToSlash(path)
+	if strings.HasPrefix(path, "//") {
+		// An empty authority, for UNC paths.
+		path = "//" + path
+	}
+	return "file:" + (&url.URL{Path: path}).EscapedPath() + "?immutable=1"
+}
+
 // Close closes the database connection.
 //
 // If the database connection is associated with unfinalized prepared statements,
@@ -81,7 +171,9 @@ func (c *Conn) Close() error {
 	}
 
 	c.SetInterrupt(context.Background())
+	c.stmtCache.clear()
 
+	defer c.unlock(c.lock())
 	r, err := c.api.close.Call(c.ctx, uint64(c.handle))
 	if err != nil {
 		panic(err)
@@ -92,6 +184,7 @@ func (c *Conn) Close() error {
 	}
 
 	c.handle = 0
+	c.clientData = nil
 	return c.mem.mod.Close(c.ctx)
 }
 
@@ -100,6 +193,11 @@ func (c *Conn) Close() error {
 //
 // https://www.sqlite.org/c3ref/exec.html
 func (c *Conn) Exec(sql string) error {
+	defer c.unlock(c.lock())
+	if util.EmptyStatement(sql) {
+		c.lastErr = nil
+		return nil
+	}
 	c.checkInterrupt()
 	defer c.arena.reset()
 	sqlPtr := c.arena.string(sql)
@@ -108,9 +206,107 @@ func (c *Conn) Exec(sql string) error {
 	if err != nil {
 		panic(err)
 	}
+	if r[0] == _OK {
+		c.lastErr = nil
+	}
 	return c.error(r[0])
 }
 
+// ExecScript runs the SQL statements in sql, one at a time,
+// and returns the number of statements executed.
+// Empty statements (whitespace, comments, and semicolons) are not counted.
+//
+// ExecScript stops at the first error.
+// For errors from executing a statement, [Error.SQL] returns the statement that failed.
+// For all errors, [Error.Offset] returns the byte offset of that statement in sql.
+func (c *Conn) ExecScript(sql string) (statements int, err error) {
+	script := sql
+	for {
+		sql = strings.TrimLeft(sql, " \t\n\r\v\f")
+		offset := len(script) - len(sql)
+
+		stmt, tail, err := c.Prepare(sql)
+		if err != nil || stmt == nil {
+			if serr, ok := err.(*Error); ok {
+				serr.offset = offset
+			}
+			return statements, err
+		}
+
+		err = stmt.Exec()
+		if cerr := stmt.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			if serr, ok := err.(*Error); ok {
+				if serr.sql == "" {
+					serr.sql = sql[:len(sql)-len(tail)]
+				}
+				serr.offset = offset
+			}
+			return statements, err
+		}
+
+		statements++
+		sql = tail
+	}
+}
+
+// ExecParams is a convenience function that prepares a single SQL statement,
+// binds args to its parameters with [Stmt.BindValue],
+// executes it, and finalizes it
+// (or keeps it for reuse, see [Conn.SetStatementCacheSize]).
+//
+// ExecParams returns an error if sql contains more than one statement.
+func (c *Conn) ExecParams(sql string, args ...any) (err error) {
+	stmt, tail, err := c.prepareCached(sql)
+	if err != nil || stmt == nil {
+		return err
+	}
+	defer c.releaseCached(sql, tail, stmt, &err)
+
+	if !util.EmptyStatement(tail) {
+		return tailErr
+	}
+
+	for i, arg := range args {
+		if err := stmt.BindValue(i+1, arg); err != nil {
+			return err
+		}
+	}
+	return stmt.Exec()
+}
+
+// Query is a convenience function that prepares the first SQL statement in sql,
+// binds args to its parameters with [Stmt.BindValue],
+// and calls fn for each row in the result set.
+// The statement is always finalized before Query returns
+// (or kept for reuse, see [Conn.SetStatementCacheSize]).
+//
+// Query stops at the first error, including errors returned by fn.
+// fn can use the [Stmt] column accessors to read the current row,
+// but must not keep a reference to it.
+func (c *Conn) Query(sql string, args []any, fn func(stmt *Stmt) error) (err error) {
+	stmt, tail, err := c.prepareCached(sql)
+	if err != nil || stmt == nil {
+		return err
+	}
+	defer c.releaseCached(sql, tail, stmt, &err)
+
+	for i, arg := range args {
+		if err := stmt.BindValue(i+1, arg); err != nil {
+			return err
+		}
+	}
+
+	for stmt.Step() {
+		if err := fn(stmt); err != nil {
+			return err
+		}
+	}
+	return stmt.Err()
+}
+
 // Prepare calls [Conn.PrepareFlags] with no flags.
 func (c *Conn) Prepare(sql string) (stmt *Stmt, tail string, err error) {
 	return c.PrepareFlags(sql, 0)
@@ -123,7 +319,8 @@ func (c *Conn) Prepare(sql string) (stmt *Stmt, tail string, err error) {
 //
 // https://www.sqlite.org/c3ref/prepare.html
 func (c *Conn) PrepareFlags(sql string, flags PrepareFlag) (stmt *Stmt, tail string, err error) {
-	if emptyStatement(sql) {
+	defer c.unlock(c.lock())
+	if util.EmptyStatement(sql) {
 		return nil, "", nil
 	}
 
@@ -139,7 +336,7 @@ func (c *Conn) PrepareFlags(sql string, flags PrepareFlag) (stmt *Stmt, tail str
 		panic(err)
 	}
 
-	stmt = &Stmt{c: c}
+	stmt = &Stmt{c: c, bindCount: -1, colCount: -1}
 	stmt.handle = c.mem.readUint32(stmtPtr)
 	i := c.mem.readUint32(tailPtr)
 	tail = sql[i-sqlPtr:]
@@ -150,6 +347,7 @@ func (c *Conn) PrepareFlags(sql string, flags PrepareFlag) (stmt *Stmt, tail str
 	if stmt.handle == 0 {
 		return nil, "", nil
 	}
+	stmt.explain = explainMode(sql)
 	return
 }
 
@@ -157,6 +355,7 @@ func (c *Conn) PrepareFlags(sql string, flags PrepareFlag) (stmt *Stmt, tail str
 //
 // https://www.sqlite.org/c3ref/get_autocommit.html
 func (c *Conn) GetAutocommit() bool {
+	defer c.unlock(c.lock())
 	r, err := c.api.autocommit.Call(c.ctx, uint64(c.handle))
 	if err != nil {
 		panic(err)
@@ -169,6 +368,7 @@ func (c *Conn) GetAutocommit() bool {
 //
 // https://www.sqlite.org/c3ref/last_insert_rowid.html
 func (c *Conn) LastInsertRowID() uint64 {
+	defer c.unlock(c.lock())
 	r, err := c.api.lastRowid.Call(c.ctx, uint64(c.handle))
 	if err != nil {
 		panic(err)
@@ -182,6 +382,7 @@ func (c *Conn) LastInsertRowID() uint64 {
 //
 // https://www.sqlite.org/c3ref/changes.html
 func (c *Conn) Changes() uint64 {
+	defer c.unlock(c.lock())
 	r, err := c.api.changes.Call(c.ctx, uint64(c.handle))
 	if err != nil {
 		panic(err)
@@ -189,6 +390,166 @@ func (c *Conn) Changes() uint64 {
 	return r[0]
 }
 
+// TotalChanges returns the number of rows modified, inserted or deleted
+// by all INSERT, UPDATE or DELETE statements completed
+// since the database connection was opened.
+//
+// The embedded SQLite binary doesn't export sqlite3_total_changes64,
+// so unless [Binary] does, this runs SELECT total_changes().
+//
+// https://www.sqlite.org/c3ref/total_changes.html
+func (c *Conn) TotalChanges() (uint64, error) {
+	if c.api.totalChanges != nil {
+		defer c.unlock(c.lock())
+		r, err := c.api.totalChanges.Call(c.ctx, uint64(c.handle))
+		if err != nil {
+			panic(err)
+		}
+		return r[0], nil
+	}
+
+	stmt, _, err := c.Prepare(`SELECT total_changes()`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	if !stmt.Step() {
+		return 0, stmt.Err()
+	}
+	return uint64(stmt.ColumnInt64(0)), nil
+}
+
+// ResetChanges records the current value of [Conn.TotalChanges]
+// as the baseline for [Conn.WindowedChanges].
+func (c *Conn) ResetChanges() error {
+	total, err := c.TotalChanges()
+	if err != nil {
+		return err
+	}
+	c.baseChanges = total
+	return nil
+}
+
+// WindowedChanges returns the number of rows modified, inserted or deleted
+// since the last call to [Conn.ResetChanges],
+// or since the database connection was opened.
+// It returns -1 if [Conn.TotalChanges] fails.
+func (c *Conn) WindowedChanges() int64 {
+	total, err := c.TotalChanges()
+	if err != nil {
+		return -1
+	}
+	return int64(total - c.baseChanges)
+}
+
+// TempStore sets where temporary tables and indices are stored.
+//
+// With [TEMP_STORE_FILE], temporary files are created in [TempDir].
+// [TEMP_STORE_MEMORY] avoids temporary files altogether,
+// which helps on read-only file systems,
+// at the cost of increased memory use for large sorts and temporary tables.
+//
+// https://www.sqlite.org/pragma.html#pragma_temp_store
+func (c *Conn) TempStore(mode TempStore) error {
+	return c.Exec(fmt.Sprintf("PRAGMA temp_store=%d;", mode))
+}
+
+// SetTimeFormat sets the default format used to encode/decode time values
+// by [Stmt.BindTimeAuto], [Stmt.ColumnTimeAuto] and [Stmt.Scan].
+//
+// With [TimeFormatDefault] (the default), time values are encoded
+// using [time.RFC3339Nano], and decoded using [TimeFormatAuto].
+func (c *Conn) SetTimeFormat(format TimeFormat) {
+	c.timeFormat = format
+}
+
+// SetSerialized sets whether the connection serializes its calls into SQLite,
+// so that it can be shared by multiple goroutines.
+// Call it before sharing the connection.
+//
+// Even if serialized, a prepared statement must not be used
+// by multiple goroutines concurrently: e.g. overlapping calls to [Stmt.Step]
+// and [Stmt.ColumnText] on the same statement are still invalid.
+// [Conn.SetInterrupt] is also not serialized.
+func (c *Conn) SetSerialized(serialized bool) {
+	c.serialized = serialized
+}
+
+func (c *Conn) lock() bool {
+	if c.serialized {
+		c.serial.Lock()
+		return true
+	}
+	return false
+}
+
+func (c *Conn) unlock(locked bool) {
+	if locked {
+		c.serial.Unlock()
+	}
+}
+
+// LastError returns the most recent error returned by the connection,
+// or by one of its prepared statements.
+// It's the same *Error that was returned, including its SQL and offset.
+// LastError returns nil after the next statement completes successfully,
+// with [Conn.Exec] or [Stmt.Step].
+//
+// https://www.sqlite.org/c3ref/errcode.html
+func (c *Conn) LastError() *Error {
+	defer c.unlock(c.lock())
+	return c.lastErr
+}
+
+// SetClientData associates value with the connection, under name.
+// Setting a nil value removes it.
+// Client data is kept in Go, and released when the connection is closed.
+//
+// https://www.sqlite.org/c3ref/get_clientdata.html
+func (c *Conn) SetClientData(name string, value any) {
+	defer c.unlock(c.lock())
+	if value == nil {
+		delete(c.clientData, name)
+		return
+	}
+	if c.clientData == nil {
+		c.clientData = map[string]any{}
+	}
+	c.clientData[name] = value
+}
+
+// ClientData returns the value associated with the connection under name,
+// see [Conn.SetClientData], or nil.
+//
+// https://www.sqlite.org/c3ref/get_clientdata.html
+func (c *Conn) ClientData(name string) any {
+	defer c.unlock(c.lock())
+	return c.clientData[name]
+}
+
+// ExtendedResultCodes enables or disables extended result codes.
+// They're enabled by default.
+// When disabled, [Error.ExtendedCode] returns the primary error code.
+//
+// The embedded SQLite binary doesn't export sqlite3_extended_result_codes,
+// so this is emulated: SQLite still returns extended result codes,
+// and the errors returned by the connection are masked
+// to their primary code. [Stmt.LastCode] is not affected.
+//
+// https://www.sqlite.org/c3ref/extended_result_codes.html
+func (c *Conn) ExtendedResultCodes(on bool) {
+	c.primaryOnly = !on
+}
+
+// decodeTimeFormat returns the default format used to decode time values.
+func (c *Conn) decodeTimeFormat() TimeFormat {
+	if c.timeFormat == TimeFormatDefault {
+		return TimeFormatAuto
+	}
+	return c.timeFormat
+}
+
 // SetInterrupt interrupts a long-running query when a context is done.
 //
 // Subsequent uses of the connection will return [INTERRUPT]
@@ -257,6 +618,43 @@ func (c *Conn) SetInterrupt(ctx context.Context) (old context.Context) {
 	return old
 }
 
+// WithTimeout calls fn with both lock waits and CPU-bound work bounded by d.
+// It sets PRAGMA busy_timeout to d, and interrupts the connection after d,
+// restoring the previous busy timeout and interrupt context when fn returns.
+//
+// If fn fails after d has elapsed, the returned error matches
+// [context.DeadlineExceeded] with [errors.Is], and still unwraps
+// to the error returned by fn, e.g. an [*Error] with the [INTERRUPT] code.
+//
+// https://www.sqlite.org/pragma.html#pragma_busy_timeout
+func (c *Conn) WithTimeout(d time.Duration, fn func() error) error {
+	var busy int64
+	err := c.Query(`PRAGMA busy_timeout`, nil, func(stmt *Stmt) error {
+		busy = stmt.ColumnInt64(0)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = c.Exec(fmt.Sprintf("PRAGMA busy_timeout=%d;", d.Milliseconds()))
+	if err != nil {
+		return err
+	}
+	defer c.Exec(fmt.Sprintf("PRAGMA busy_timeout=%d;", busy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	old := c.SetInterrupt(ctx)
+	defer c.SetInterrupt(old)
+
+	err = fn()
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return &interruptError{ctx: context.DeadlineExceeded, err: err}
+	}
+	return err
+}
+
 func (c *Conn) checkInterrupt() bool {
 	if c.interrupt == nil || c.interrupt.Err() == nil {
 		return false
@@ -276,6 +674,47 @@ func (c *Conn) sendInterrupt() {
 	}
 }
 
+// Begin starts a transaction of the given kind.
+//
+// A deferred transaction acquires locks as it needs them,
+// so two deferred transactions that read, then write,
+// can't both proceed, and one fails with [BUSY] when it writes.
+// An immediate transaction starts writing at once,
+// so it fails with [BUSY] (after the busy timeout) at Begin instead,
+// before doing any work.
+//
+// https://www.sqlite.org/lang_transaction.html
+func (c *Conn) Begin(kind TxKind) error {
+	switch kind {
+	case TX_DEFERRED:
+		return c.Exec(`BEGIN DEFERRED`)
+	case TX_IMMEDIATE:
+		return c.Exec(`BEGIN IMMEDIATE`)
+	case TX_EXCLUSIVE:
+		return c.Exec(`BEGIN EXCLUSIVE`)
+	default:
+		return fmt.Errorf("sqlite3: invalid transaction kind: %d", kind)
+	}
+}
+
+// Commit commits the current transaction.
+//
+// https://www.sqlite.org/lang_transaction.html
+func (c *Conn) Commit() error {
+	return c.Exec(`COMMIT`)
+}
+
+// Rollback rolls back the current transaction.
+// Rollback clears the interrupt context for its duration,
+// so it succeeds even if the connection has been interrupted.
+//
+// https://www.sqlite.org/lang_transaction.html
+func (c *Conn) Rollback() error {
+	old := c.SetInterrupt(context.Background())
+	defer c.SetInterrupt(old)
+	return c.Exec(`ROLLBACK`)
+}
+
 // Savepoint creates a named SQLite transaction using SAVEPOINT.
 //
 // On success Savepoint returns a release func that will call
@@ -380,6 +819,11 @@ func (c *Conn) error(rc uint64, sql ...string) error {
 	case err.str, "not an error":
 		err.msg = ""
 	}
+	if c.primaryOnly {
+		// Emulates sqlite3_extended_result_codes(db, 0).
+		err.code &= 0xff
+	}
+	c.lastErr = &err
 	return &err
 }
 
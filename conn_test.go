@@ -164,3 +164,21 @@ func TestConn_free(t *testing.T) {
 
 	db.free(ptr)
 }
+
+func Test_immutableURI(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"test.db", "file:test.db?immutable=1"},
+		{"testdata/test.db", "file:testdata/test.db?immutable=1"},
+		{"/tmp/test.db", "file:/tmp/test.db?immutable=1"},
+		{"/tmp/a b/x?#.db", "file:/tmp/a%20b/x%3F%23.db?immutable=1"},
+		{"//host/share/test.db", "file:////host/share/test.db?immutable=1"},
+	}
+	for _, tt := range tests {
+		if got := immutableURI(tt.path); got != tt.want {
+			t.Errorf("immutableURI(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -184,6 +184,29 @@ const (
 	PREPARE_NO_VTAB    PrepareFlag = 0x04
 )
 
+// TempStore is a location for temporary tables and indices,
+// used by [Conn.TempStore].
+//
+// https://www.sqlite.org/pragma.html#pragma_temp_store
+type TempStore uint32
+
+const (
+	TEMP_STORE_DEFAULT TempStore = 0
+	TEMP_STORE_FILE    TempStore = 1
+	TEMP_STORE_MEMORY  TempStore = 2
+)
+
+// TxKind is the kind of transaction started by [Conn.Begin].
+//
+// https://www.sqlite.org/lang_transaction.html#deferred_immediate_and_exclusive_transactions
+type TxKind uint32
+
+const (
+	TX_DEFERRED  TxKind = 0
+	TX_IMMEDIATE TxKind = 1
+	TX_EXCLUSIVE TxKind = 2
+)
+
 // Datatype is a fundamental datatype of SQLite.
 //
 // https://www.sqlite.org/c3ref/c_blob.html
@@ -0,0 +1,59 @@
+package driver
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+// AddrScanner returns a [sql.Scanner] that decodes an IP address into dest,
+// which must be a *[netip.Addr] or a *[net.IP].
+//
+// Addresses are stored as text in their canonical form;
+// they can also be decoded from a 4 or 16 byte BLOB:
+//
+//	var addr netip.Addr
+//	err := db.QueryRow(`SELECT addr FROM hosts`).Scan(driver.AddrScanner(&addr))
+//
+// NULL is scanned as the zero [netip.Addr], or a nil [net.IP].
+func AddrScanner(dest any) sql.Scanner {
+	return addrScanner{dest}
+}
+
+type addrScanner struct {
+	dest any
+}
+
+func (s addrScanner) Scan(src any) (err error) {
+	var addr netip.Addr
+	switch v := src.(type) {
+	case nil:
+	case string:
+		addr, err = netip.ParseAddr(v)
+	case []byte:
+		var ok bool
+		if addr, ok = netip.AddrFromSlice(v); !ok {
+			err = fmt.Errorf("sqlite3: invalid address: %d byte BLOB", len(v))
+		}
+	default:
+		err = fmt.Errorf("sqlite3: invalid address: %T", src)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch d := s.dest.(type) {
+	case *netip.Addr:
+		*d = addr
+	case *net.IP:
+		if addr.IsValid() {
+			*d = net.IP(addr.AsSlice())
+		} else {
+			*d = nil
+		}
+	default:
+		return fmt.Errorf("sqlite3: unsupported address destination %T", s.dest)
+	}
+	return nil
+}
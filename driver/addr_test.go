@@ -0,0 +1,80 @@
+package driver
+
+import (
+	"database/sql"
+	"net"
+	"net/netip"
+	"testing"
+)
+
+func TestAddrScanner(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, s := range []string{
+		"192.0.2.1",
+		"::ffff:192.0.2.1",
+		"2001:db8::1",
+		"fe80::1%eth0",
+	} {
+		want := netip.MustParseAddr(s)
+
+		var text string
+		var got netip.Addr
+		err := db.QueryRow(`SELECT ?, ?`, want, want).Scan(&text, AddrScanner(&got))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text != s {
+			t.Errorf("got %q, want %q", text, s)
+		}
+		if got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		var ip net.IP
+		err = db.QueryRow(`SELECT ?`, net.IP(want.AsSlice())).Scan(AddrScanner(&ip))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ip.Equal(want.AsSlice()) {
+			t.Errorf("got %v, want %v", ip, want)
+		}
+	}
+
+	var blob netip.Addr
+	err = db.QueryRow(`SELECT x'C0000201'`).Scan(AddrScanner(&blob))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := netip.MustParseAddr("192.0.2.1"); blob != want {
+		t.Errorf("got %v, want %v", blob, want)
+	}
+
+	var null sql.NullString
+	err = db.QueryRow(`SELECT ?`, netip.Addr{}).Scan(&null)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if null.Valid {
+		t.Errorf("got %v, want NULL", null)
+	}
+
+	ip := net.IP{127, 0, 0, 1}
+	err = db.QueryRow(`SELECT NULL`).Scan(AddrScanner(&ip))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ip != nil {
+		t.Errorf("got %v, want nil", ip)
+	}
+
+	var got netip.Addr
+	err = db.QueryRow(`SELECT 'abc'`).Scan(AddrScanner(&got))
+	if err == nil {
+		t.Error("want error")
+	}
+}
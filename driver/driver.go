@@ -1,17 +1,71 @@
 // Package driver provides a database/sql driver for SQLite.
+//
+// The _pragma DSN parameter sets pragmas on every new connection,
+// in order, e.g. _pragma=foreign_keys(on),busy_timeout(5000).
+// It can be repeated, or take a comma-separated list.
+//
+// Named arguments ([sql.Named]) are bound to the parameters with that name,
+// and any of the :name, @name and $name prefixes.
+// Positional arguments are bound by position, which is the parameter index:
+// "SELECT ?, ?3, ?" has parameters ?1, ?3 and ?4, and takes 4 arguments.
+// If that parameter is bound by name, the first unbound parameter is used.
+//
+// A query can have multiple statements, each a result set,
+// see [sql.Rows.NextResultSet].
+// Arguments are bound to the first statement,
+// and later statements can't have parameters.
+// Statements that aren't read are run when the rows are closed,
+// unless an earlier statement fails.
+//
+// Transactions are always serializable:
+// [sql.LevelDefault], [sql.LevelSerializable] and [sql.LevelLinearizable]
+// are accepted, other isolation levels return an error.
+// Read-write transactions begin with BEGIN, or the kind
+// set by the _txlock DSN parameter (deferred, immediate or exclusive).
+// Read-only transactions begin with BEGIN deferred,
+// and set PRAGMA query_only for their duration.
+//
+// The _time_format DSN parameter sets how [time.Time] arguments are stored:
+// one of the named [sqlite3.TimeFormat] values (e.g. unixepoch),
+// or a Go layout, e.g. _time_format=2006-01-02 15:04:05.
+// It defaults to [time.RFC3339Nano].
+//
+// [sql.Result.RowsAffected] is the number of rows changed by the last
+// INSERT, UPDATE or DELETE statement executed, read as soon as it completes.
+// Rows skipped by OR IGNORE are not counted, and a row replaced
+// by OR REPLACE (or REPLACE) counts once, as the implicit delete is not counted.
+// Changes made by triggers are not counted either.
+//
+// Scanning into *any gives the natural Go type of each value:
+// int64, float64, string, []byte, or nil for NULL.
+// Text in the time format, if it's a text format,
+// is returned as a [time.Time], if it round-trips exactly;
+// other text, and numeric time values, are returned as stored,
+// see [TimeScanner].
+// The _time_raw DSN parameter (e.g. _time_raw=true)
+// returns all text as stored, for generic row dumpers.
+//
+// [netip.Addr] and [net.IP] arguments are stored as text, in canonical form,
+// and the zero Addr and a nil IP as NULL.
+// Use [AddrScanner] to scan them back.
 package driver
 
 import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"encoding"
 	"fmt"
 	"io"
+	"net"
+	"net/netip"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ncruces/go-sqlite3"
+	"github.com/ncruces/go-sqlite3/internal/util"
 )
 
 func init() {
@@ -26,24 +80,43 @@ func (sqlite) Open(name string) (driver.Conn, error) {
 		return nil, err
 	}
 
-	var txBegin string
+	txBegin := "BEGIN"
+	var tmFormat sqlite3.TimeFormat
+	var tmRaw bool
 	var pragmas strings.Builder
 	if _, after, ok := strings.Cut(name, "?"); ok {
 		query, _ := url.ParseQuery(after)
 
 		switch s := query.Get("_txlock"); s {
 		case "":
-			txBegin = "BEGIN"
 		case "deferred", "immediate", "exclusive":
 			txBegin = "BEGIN " + s
 		default:
+			c.Close()
 			return nil, fmt.Errorf("sqlite3: invalid _txlock: %s", s)
 		}
 
+		tmFormat = sqlite3.TimeFormat(query.Get("_time_format"))
+		if err := checkTimeFormat(tmFormat); err != nil {
+			c.Close()
+			return nil, err
+		}
+		c.SetTimeFormat(tmFormat)
+
+		if s := query.Get("_time_raw"); s != "" {
+			tmRaw, err = strconv.ParseBool(s)
+			if err != nil {
+				c.Close()
+				return nil, fmt.Errorf("sqlite3: invalid _time_raw: %s", s)
+			}
+		}
+
 		for _, p := range query["_pragma"] {
-			pragmas.WriteString(`PRAGMA `)
-			pragmas.WriteString(p)
-			pragmas.WriteByte(';')
+			for _, p := range splitPragmas(p) {
+				pragmas.WriteString(`PRAGMA `)
+				pragmas.WriteString(p)
+				pragmas.WriteByte(';')
+			}
 		}
 	}
 	if pragmas.Len() == 0 {
@@ -53,40 +126,98 @@ func (sqlite) Open(name string) (driver.Conn, error) {
 
 	err = c.Exec(pragmas.String())
 	if err != nil {
+		c.Close()
 		return nil, fmt.Errorf("sqlite3: invalid _pragma: %w", err)
 	}
 	return conn{
-		conn:    c,
-		txBegin: txBegin,
+		conn:     c,
+		txBegin:  txBegin,
+		tmFormat: tmFormat,
+		tmRaw:    tmRaw,
+	}, nil
+}
+
+// NewConnector returns a [driver.Connector] that uses open
+// to create new connections, for use with [sql.OpenDB].
+//
+// This allows connections to be configured (e.g. with pragmas)
+// before they're handed to the connection pool.
+// Transactions use BEGIN deferred, and no pragmas are executed:
+// in particular, the embedded SQLite defaults to locking_mode=exclusive,
+// so a connector that opens many connections to the same database file
+// should execute PRAGMA locking_mode=normal, as [sql.Open] does.
+//
+// To share a single connection, open must return it exactly once,
+// and the pool should be limited with [sql.DB.SetMaxOpenConns](1).
+// Once the pool closes that connection, it cannot open another.
+func NewConnector(open func() (*sqlite3.Conn, error)) driver.Connector {
+	return connector{open}
+}
+
+type connector struct {
+	open func() (*sqlite3.Conn, error)
+}
+
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	s, err := c.open()
+	if err != nil {
+		return nil, err
+	}
+	return conn{
+		conn:    s,
+		txBegin: "BEGIN",
 	}, nil
 }
 
+func (connector) Driver() driver.Driver {
+	return sqlite{}
+}
+
 type conn struct {
 	conn       *sqlite3.Conn
 	txBegin    string
 	txReadOnly bool
+	tmFormat   sqlite3.TimeFormat
+	tmRaw      bool
 }
 
 var (
 	// Ensure these interfaces are implemented:
-	_ driver.ExecerContext = conn{}
-	_ driver.ConnBeginTx   = conn{}
+	_ driver.ExecerContext     = conn{}
+	_ driver.QueryerContext    = conn{}
+	_ driver.ConnBeginTx       = conn{}
+	_ driver.SessionResetter   = conn{}
+	_ driver.NamedValueChecker = conn{}
 )
 
 func (c conn) Close() error {
 	return c.conn.Close()
 }
 
+func (c conn) ResetSession(ctx context.Context) error {
+	// Clear any interrupt, and roll back a transaction left open,
+	// e.g. with a raw BEGIN on a [sql.Conn].
+	c.conn.SetInterrupt(context.Background())
+	if !c.conn.GetAutocommit() {
+		return c.conn.Exec(`ROLLBACK`)
+	}
+	return nil
+}
+
+func (c conn) CheckNamedValue(arg *driver.NamedValue) error {
+	return checkNamedValue(arg)
+}
+
 func (c conn) Begin() (driver.Tx, error) {
 	return c.BeginTx(context.Background(), driver.TxOptions{})
 }
 
 func (c conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
-	switch opts.Isolation {
+	// SQLite transactions are always serializable.
+	switch sql.IsolationLevel(opts.Isolation) {
 	default:
-		return nil, isolationErr
-	case driver.IsolationLevel(sql.LevelDefault):
-	case driver.IsolationLevel(sql.LevelSerializable):
+		return nil, fmt.Errorf("%w: %v", isolationErr, sql.IsolationLevel(opts.Isolation))
+	case sql.LevelDefault, sql.LevelSerializable, sql.LevelLinearizable:
 	}
 
 	txBegin := c.txBegin
@@ -117,7 +248,14 @@ func (c conn) Commit() error {
 }
 
 func (c conn) Rollback() error {
-	return c.conn.Exec(`ROLLBACK`)
+	err := c.conn.Exec(`ROLLBACK`)
+	if c.txReadOnly {
+		// Restore writes for the next transaction.
+		if qerr := c.conn.Exec(`PRAGMA query_only=off`); err == nil {
+			err = qerr
+		}
+	}
+	return err
 }
 
 func (c conn) Prepare(query string) (driver.Stmt, error) {
@@ -138,21 +276,34 @@ func (c conn) Prepare(query string) (driver.Stmt, error) {
 			return nil, tailErr
 		}
 	}
-	return stmt{s, c.conn}, nil
+	names := s.BindNames()
+	return stmt{stmt: s, conn: c.conn, sql: query, names: names, tmFormat: c.tmFormat, tmRaw: c.tmRaw}, nil
 }
 
 func (c conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
-	if len(args) != 0 {
-		// Slow path.
-		return nil, driver.ErrSkip
-	}
-
 	old := c.conn.SetInterrupt(ctx)
 	defer c.conn.SetInterrupt(old)
 
-	err := c.conn.Exec(query)
-	if err != nil {
-		return nil, err
+	if len(args) == 0 {
+		err := c.conn.Exec(query)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		s, tail, err := c.prepareArgs(query, args)
+		if err != nil {
+			return nil, err
+		}
+		err = s.Exec()
+		if cerr := s.Close(); err == nil {
+			err = cerr
+		}
+		if err == nil {
+			err = execTail(c.conn, tail)
+		}
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return result{
@@ -161,9 +312,52 @@ func (c conn) ExecContext(ctx context.Context, query string, args []driver.Named
 	}, nil
 }
 
+func (c conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	s, tail, err := c.prepareArgs(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each statement in query is a result set,
+	// see [rows.NextResultSet].
+	r := &rows{ctx: ctx, stmt: s, conn: c.conn, tail: tail, owned: true, tmFormat: c.tmFormat, tmRaw: c.tmRaw}
+	if err := r.exec(); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// prepareArgs prepares the first statement in query, and binds args to it.
+// The statements in tail must not have parameters, see [execTail].
+func (c conn) prepareArgs(query string, args []driver.NamedValue) (*sqlite3.Stmt, string, error) {
+	s, tail, err := c.conn.Prepare(query)
+	if err != nil {
+		return nil, "", err
+	}
+	if s == nil {
+		return nil, "", driver.ErrSkip
+	}
+
+	if len(args) != 0 || s.BindCount() != 0 {
+		sql := query[:len(query)-len(tail)]
+		names := s.BindNames()
+		st := stmt{stmt: s, conn: c.conn, sql: sql, names: names, tmFormat: c.tmFormat, tmRaw: c.tmRaw}
+		if err := st.bind(args); err != nil {
+			s.Close()
+			return nil, "", err
+		}
+	}
+	return s, tail, nil
+}
+
 type stmt struct {
-	stmt *sqlite3.Stmt
-	conn *sqlite3.Conn
+	stmt     *sqlite3.Stmt
+	conn     *sqlite3.Conn
+	sql      string
+	names    []string
+	tmFormat sqlite3.TimeFormat
+	tmRaw    bool
 }
 
 var (
@@ -178,13 +372,9 @@ func (s stmt) Close() error {
 }
 
 func (s stmt) NumInput() int {
-	n := s.stmt.BindCount()
-	for i := 1; i <= n; i++ {
-		if s.stmt.BindName(i) != "" {
-			return -1
-		}
-	}
-	return n
+	// Arguments are checked by QueryContext,
+	// which gives a more descriptive error.
+	return -1
 }
 
 // Deprecated: use ExecContext instead.
@@ -198,13 +388,13 @@ func (s stmt) Query(args []driver.Value) (driver.Rows, error) {
 }
 
 func (s stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
-	// Use QueryContext to setup bindings.
-	// No need to close rows: that simply resets the statement, exec does the same.
-	_, err := s.QueryContext(ctx, args)
+	err := s.bind(args)
 	if err != nil {
 		return nil, err
 	}
 
+	// Step to completion, even if the statement returns rows,
+	// like INSERT ... RETURNING.
 	err = s.stmt.Exec()
 	if err != nil {
 		return nil, err
@@ -217,60 +407,192 @@ func (s stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver
 }
 
 func (s stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
-	err := s.stmt.ClearBindings()
+	err := s.bind(args)
 	if err != nil {
 		return nil, err
 	}
 
+	r := &rows{ctx: ctx, stmt: s.stmt, conn: s.conn, tmFormat: s.tmFormat, tmRaw: s.tmRaw}
+	if err := r.exec(); err != nil {
+		s.stmt.Reset()
+		return nil, err
+	}
+	return r, nil
+}
+
+// bind binds args to the statement's parameters.
+//
+// Named arguments are bound to the parameters with that name,
+// and any of the :name, @name and $name prefixes.
+// Positional arguments are bound by their ordinal, which is the index
+// of the parameter, following SQLite's numbering:
+// ?NNN has index NNN, and a bare ? takes the index
+// after the largest assigned so far, so "SELECT ?, ?3, ?"
+// has 4 parameters, and takes 4 arguments.
+// A positional argument whose index is bound by a named argument
+// takes the first parameter that isn't bound instead.
+//
+// It's an error if a named argument matches no parameter,
+// if a positional argument has no parameter to bind to,
+// or if a parameter is left unbound,
+// other than at the ordinal of a named argument.
+func (s stmt) bind(args []driver.NamedValue) error {
+	err := s.stmt.ClearBindings()
+	if err != nil {
+		return err
+	}
+
+	// Parameters bound by name, or by position.
+	named := make([]bool, len(s.names)+1)
+	bound := make([]bool, len(s.names)+1)
+
 	var ids [3]int
 	for _, arg := range args {
-		ids := ids[:0]
 		if arg.Name == "" {
-			ids = append(ids, arg.Ordinal)
-		} else {
-			for _, prefix := range []string{":", "@", "$"} {
-				if id := s.stmt.BindIndex(prefix + arg.Name); id != 0 {
-					ids = append(ids, id)
-				}
+			continue
+		}
+		ids := namedIndexes(ids[:0], s.names, arg.Name)
+		if len(ids) == 0 {
+			return fmt.Errorf("sqlite3: unknown named parameter: %s", arg.Name)
+		}
+		for _, id := range ids {
+			if err := s.bindValue(id, arg.Value); err != nil {
+				return err
 			}
+			named[id] = true
+			bound[id] = true
 		}
+	}
 
-		for _, id := range ids {
-			switch a := arg.Value.(type) {
-			case bool:
-				err = s.stmt.BindBool(id, a)
-			case int:
-				err = s.stmt.BindInt(id, a)
-			case int64:
-				err = s.stmt.BindInt64(id, a)
-			case float64:
-				err = s.stmt.BindFloat(id, a)
-			case string:
-				err = s.stmt.BindText(id, a)
-			case []byte:
-				err = s.stmt.BindBlob(id, a)
-			case sqlite3.ZeroBlob:
-				err = s.stmt.BindZeroBlob(id, int64(a))
-			case time.Time:
-				err = s.stmt.BindText(id, a.Format(time.RFC3339Nano))
-			case nil:
-				err = s.stmt.BindNull(id)
-			default:
-				panic(assertErr)
+	next := 1
+	for _, arg := range args {
+		if arg.Name != "" {
+			continue
+		}
+		id := arg.Ordinal
+		if id >= len(bound) || bound[id] {
+			// Take the first parameter that isn't bound.
+			for next < len(bound) && bound[next] {
+				next++
 			}
+			id = next
 		}
-		if err != nil {
-			return nil, err
+		if id >= len(bound) {
+			return s.argCountErr(args)
+		}
+		if err := s.bindValue(id, arg.Value); err != nil {
+			return err
+		}
+		bound[id] = true
+	}
+
+	for id := 1; id < len(bound); id++ {
+		if !bound[id] && !isNamedOrdinal(args, id) {
+			return s.argCountErr(args)
+		}
+	}
+	return nil
+}
+
+// isNamedOrdinal reports if the argument at ordinal is named.
+func isNamedOrdinal(args []driver.NamedValue, ordinal int) bool {
+	for _, arg := range args {
+		if arg.Ordinal == ordinal {
+			return arg.Name != ""
 		}
 	}
+	return false
+}
+
+func (s stmt) bindValue(id int, value any) error {
+	switch a := value.(type) {
+	case bool:
+		return s.stmt.BindBool(id, a)
+	case int:
+		return s.stmt.BindInt(id, a)
+	case int64:
+		return s.stmt.BindInt64(id, a)
+	case float64:
+		return s.stmt.BindFloat(id, a)
+	case string:
+		return s.stmt.BindText(id, a)
+	case []byte:
+		return s.stmt.BindBlob(id, a)
+	case sqlite3.ZeroBlob:
+		return s.stmt.BindZeroBlob(id, int64(a))
+	case time.Time:
+		return s.stmt.BindTime(id, a, s.tmFormat)
+	case time.Duration:
+		return s.stmt.BindDuration(id, a, time.Nanosecond)
+	case nil:
+		return s.stmt.BindNull(id)
+	default:
+		panic(assertErr)
+	}
+}
 
-	return rows{ctx, s.stmt, s.conn}, nil
+// argCountErr reports a mismatch between args and the statement's parameters.
+func (s stmt) argCountErr(args []driver.NamedValue) error {
+	params := make([]string, len(s.names))
+	for i, name := range s.names {
+		if name == "" {
+			name = "?" + strconv.Itoa(i+1)
+		}
+		params[i] = name
+	}
+	return fmt.Errorf("sqlite3: query %q has %d parameters %v, got %d arguments",
+		s.sql, len(params), params, len(args))
 }
 
 func (s stmt) CheckNamedValue(arg *driver.NamedValue) error {
-	switch arg.Value.(type) {
+	return checkNamedValue(arg)
+}
+
+// checkNamedValue accepts the types the driver binds directly.
+// Other values are converted, in order of precedence:
+// [driver.Valuer] values by database/sql;
+// values of a basic kind (e.g. an int enum) by the [driver.DefaultParameterConverter];
+// and, if that fails, [encoding.TextMarshaler] and [fmt.Stringer] values to text.
+func checkNamedValue(arg *driver.NamedValue) error {
+	switch v := arg.Value.(type) {
 	case bool, int, int64, float64, string, []byte,
-		sqlite3.ZeroBlob, time.Time, nil:
+		sqlite3.ZeroBlob, time.Time, time.Duration, nil:
+		return nil
+	case netip.Addr:
+		if v.IsValid() {
+			arg.Value = v.String()
+		} else {
+			arg.Value = nil
+		}
+		return nil
+	case net.IP:
+		if v != nil {
+			arg.Value = v.String()
+		} else {
+			arg.Value = nil
+		}
+		return nil
+	case driver.Valuer:
+		return driver.ErrSkip
+	}
+
+	// Convert by kind, like database/sql would,
+	// so types with a String method keep their stored representation.
+	if v, err := driver.DefaultParameterConverter.ConvertValue(arg.Value); err == nil {
+		arg.Value = v
+		return nil
+	}
+
+	switch v := arg.Value.(type) {
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return err
+		}
+		arg.Value = string(text)
+		return nil
+	case fmt.Stringer:
+		arg.Value = v.String()
 		return nil
 	default:
 		return driver.ErrSkip
@@ -288,16 +610,104 @@ func (r result) RowsAffected() (int64, error) {
 }
 
 type rows struct {
-	ctx  context.Context
-	stmt *sqlite3.Stmt
-	conn *sqlite3.Conn
+	ctx   context.Context
+	stmt  *sqlite3.Stmt
+	conn  *sqlite3.Conn
+	tail  string
+	owned bool
+
+	tmFormat sqlite3.TimeFormat
+	tmRaw    bool
+
+	// Columns may step the statement ahead of Next,
+	// see [rows.Columns].
+	started bool
+	pending bool
+	row     bool
 }
 
-func (r rows) Close() error {
-	return r.stmt.Reset()
+var (
+	// Ensure these interfaces are implemented:
+	_ driver.RowsNextResultSet = &rows{}
+)
+
+// Close runs any statements that remain in the query,
+// so their changes aren't discarded,
+// unless the current statement failed.
+func (r *rows) Close() error {
+	if !r.owned {
+		return r.stmt.Reset()
+	}
+
+	err := r.stmt.Close()
+	if err == nil && r.HasNextResultSet() {
+		old := r.conn.SetInterrupt(r.ctx)
+		defer r.conn.SetInterrupt(old)
+		err = execTail(r.conn, r.tail)
+	}
+	r.tail = ""
+	return err
+}
+
+func (r *rows) HasNextResultSet() bool {
+	return r.owned && !util.EmptyStatement(r.tail)
 }
 
-func (r rows) Columns() []string {
+func (r *rows) NextResultSet() error {
+	if !r.HasNextResultSet() {
+		return io.EOF
+	}
+
+	s, tail, err := r.conn.Prepare(r.tail)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		r.tail = ""
+		return io.EOF
+	}
+	if s.BindCount() != 0 {
+		s.Close()
+		return paramsErr
+	}
+
+	err = r.stmt.Close()
+	if err != nil {
+		s.Close()
+		return err
+	}
+	r.stmt = s
+	r.tail = tail
+	r.started = false
+	r.pending = false
+	return r.exec()
+}
+
+// exec runs a statement that returns no columns (a command, like DDL)
+// to completion, so it takes effect even if its rows are never read.
+// Statements that return columns, including INSERT ... RETURNING,
+// are stepped lazily, by [rows.Next].
+func (r *rows) exec() error {
+	if r.stmt.ColumnCount() != 0 {
+		return nil
+	}
+	for r.step() {
+	}
+	r.row = false
+	r.started = true
+	r.pending = true
+	return r.stmt.Err()
+}
+
+func (r *rows) Columns() []string {
+	// SQLite only reprepares a statement after a schema change when it's stepped.
+	// Step now, so the column count reflects the current schema.
+	if !r.started {
+		r.row = r.step()
+		r.started = true
+		r.pending = true
+	}
+
 	count := r.stmt.ColumnCount()
 	columns := make([]string, count)
 	for i := range columns {
@@ -306,11 +716,21 @@ func (r rows) Columns() []string {
 	return columns
 }
 
-func (r rows) Next(dest []driver.Value) error {
+func (r *rows) step() bool {
 	old := r.conn.SetInterrupt(r.ctx)
 	defer r.conn.SetInterrupt(old)
+	return r.stmt.Step()
+}
 
-	if !r.stmt.Step() {
+func (r *rows) Next(dest []driver.Value) error {
+	row := r.row
+	if !r.pending {
+		row = r.step()
+	}
+	r.started = true
+	r.pending = false
+
+	if !row {
 		if err := r.stmt.Err(); err != nil {
 			return err
 		}
@@ -324,16 +744,20 @@ func (r rows) Next(dest []driver.Value) error {
 		case sqlite3.FLOAT:
 			dest[i] = r.stmt.ColumnFloat(i)
 		case sqlite3.TEXT:
-			dest[i] = maybeTime(r.stmt.ColumnText(i))
+			if r.tmRaw {
+				dest[i] = r.stmt.ColumnText(i)
+			} else {
+				dest[i] = maybeTime(r.stmt.ColumnText(i), r.tmFormat)
+			}
 		case sqlite3.BLOB:
 			buf, _ := dest[i].([]byte)
+			if buf == nil {
+				// An empty BLOB is not NULL.
+				buf = []byte{}
+			}
 			dest[i] = r.stmt.ColumnBlob(i, buf)
 		case sqlite3.NULL:
-			if buf, ok := dest[i].([]byte); ok {
-				dest[i] = buf[0:0]
-			} else {
-				dest[i] = nil
-			}
+			dest[i] = nil
 		default:
 			panic(assertErr)
 		}
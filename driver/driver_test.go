@@ -5,9 +5,12 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
 	"math"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -54,6 +57,38 @@ func Test_Open_pragma(t *testing.T) {
 	}
 }
 
+func Test_Open_pragma_list(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:"+
+		filepath.Join(t.TempDir(), "test.db")+
+		"?_pragma=foreign_keys(on),busy_timeout(5000)&_pragma=locking_mode(normal)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	// Force two connections, and check that both have the pragmas.
+	for i := 0; i < 2; i++ {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		var keys bool
+		var timeout int
+		var mode string
+		err = conn.QueryRowContext(ctx, `SELECT * FROM pragma_foreign_keys, pragma_busy_timeout, pragma_locking_mode`).Scan(&keys, &timeout, &mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !keys || timeout != 5000 || mode != "normal" {
+			t.Errorf("got (%v, %d, %q), want (true, 5000, normal)", keys, timeout, mode)
+		}
+	}
+}
+
 func Test_Open_pragma_invalid(t *testing.T) {
 	db, err := sql.Open("sqlite3", "file::memory:?_pragma=busy_timeout+1000")
 	if err != nil {
@@ -132,6 +167,22 @@ func Test_Open_txLock_invalid(t *testing.T) {
 	}
 }
 
+func Test_Open_timeRaw_invalid(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file::memory:?_time_raw=maybe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.Conn(context.TODO())
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if got := err.Error(); got != `sqlite3: invalid _time_raw: maybe` {
+		t.Error("got message: ", got)
+	}
+}
+
 func Test_BeginTx(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -143,9 +194,21 @@ func Test_BeginTx(t *testing.T) {
 	defer db.Close()
 
 	_, err = db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
-	if err.Error() != string(isolationErr) {
+	if !errors.Is(err, isolationErr) {
 		t.Error("want isolationErr")
 	}
+	if got := err.Error(); got != `sqlite3: unsupported isolation level: Read Committed` {
+		t.Error("got message: ", got)
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelLinearizable})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	tx1, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
@@ -231,6 +294,124 @@ func Test_Prepare(t *testing.T) {
 	}
 }
 
+func Test_Query_resultSets(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+		SELECT 1 UNION ALL SELECT 2;
+		SELECT 'three';
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var got []any
+	for {
+		for rows.Next() {
+			var v any
+			err = rows.Scan(&v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, v)
+		}
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []any{int64(1), int64(2), "three"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func Test_Query_resultSets_tail(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE test (col)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Statements that aren't read run on Close.
+	rows, err := db.Query(`SELECT 1; INSERT INTO test VALUES (1); INSERT INTO test VALUES (2)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Arguments are bound to the first statement.
+	rows, err = db.Query(`SELECT ?; SELECT count(*) FROM test; -- done`, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []int
+	for {
+		for rows.Next() {
+			var v int
+			if err := rows.Scan(&v); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, v)
+		}
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{5, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Later statements can't have parameters.
+	_, err = db.Exec(`INSERT INTO test VALUES (?); INSERT INTO test VALUES (?)`, 3)
+	if err == nil || err.Error() != string(paramsErr) {
+		t.Errorf("got %v, want paramsErr", err)
+	}
+	rows, err = db.Query(`SELECT 1; SELECT ?`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rows.Close(); err == nil || err.Error() != string(paramsErr) {
+		t.Errorf("got %v, want paramsErr", err)
+	}
+
+	// Trailing comments and semicolons are not a result set.
+	c, err := sqlite{}.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	r, err := c.(driver.QueryerContext).QueryContext(context.Background(), `SELECT 1; ; -- done`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if r.(driver.RowsNextResultSet).HasNextResultSet() {
+		t.Error("want no next result set")
+	}
+}
+
 func Test_QueryRow_named(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -281,6 +462,112 @@ func Test_QueryRow_named(t *testing.T) {
 	}
 }
 
+func Test_QueryRow_named_mixed(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	row := db.QueryRow(`SELECT ?1, :colon, @at, $dollar, ?5`,
+		1, sql.Named("colon", 2), sql.Named("at", 3), sql.Named("dollar", 4), 5)
+
+	var got [5]int
+	err = row.Scan(&got[0], &got[1], &got[2], &got[3], &got[4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [5]int{1, 2, 3, 4, 5}; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	err = db.QueryRow(`SELECT ?, :colon`, 1, sql.Named("missing", 2)).Scan(&got[0], &got[1])
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if got := err.Error(); got != `sqlite3: unknown named parameter: missing` {
+		t.Error("got message: ", got)
+	}
+}
+
+func Test_QueryRow_named_positional(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Positional arguments whose position is bound by name
+	// take the first parameter that isn't bound.
+	var got [2]int
+	err = db.QueryRow(`SELECT ?, :a`, sql.Named("a", 1), 5).Scan(&got[0], &got[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [2]int{5, 1}; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	var all [6]int
+	err = db.QueryRow(`SELECT :colon, ?, @at, ?, $dollar, ?`,
+		sql.Named("dollar", 5), 2, sql.Named("colon", 1), 4, sql.Named("at", 3), 6).
+		Scan(&all[0], &all[1], &all[2], &all[3], &all[4], &all[5])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [6]int{1, 2, 3, 4, 5, 6}; all != want {
+		t.Errorf("got %v, want %v", all, want)
+	}
+
+	// Named parameters not bound by name take positional arguments.
+	err = db.QueryRow(`SELECT :a, :b`, sql.Named("b", 2), 1).Scan(&got[0], &got[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [2]int{1, 2}; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Too many, or too few, positional arguments.
+	err = db.QueryRow(`SELECT ?, :a`, sql.Named("a", 1), 5, 6).Scan(&got[0], &got[1])
+	if err == nil {
+		t.Error("want error")
+	} else if got := err.Error(); got != `sqlite3: query "SELECT ?, :a" has 2 parameters [?1 :a], got 3 arguments` {
+		t.Error("got message: ", got)
+	}
+	err = db.QueryRow(`SELECT ?, :a, ?`, sql.Named("a", 1), 5).Scan(&got[0], &got[1], &all[0])
+	if err == nil {
+		t.Error("want error")
+	} else if got := err.Error(); got != `sqlite3: query "SELECT ?, :a, ?" has 3 parameters [?1 :a ?3], got 2 arguments` {
+		t.Error("got message: ", got)
+	}
+}
+
+func Test_QueryRow_argCount(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var a, b, c int
+	err = db.QueryRow(`SELECT ?, ?, ?`, 1, 2).Scan(&a, &b, &c)
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if got := err.Error(); got != `sqlite3: query "SELECT ?, ?, ?" has 3 parameters [?1 ?2 ?3], got 2 arguments` {
+		t.Error("got message: ", got)
+	}
+
+	err = db.QueryRow(`SELECT ?1, :b`, 1, 2, 3).Scan(&a, &b)
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if got := err.Error(); got != `sqlite3: query "SELECT ?1, :b" has 2 parameters [?1 :b], got 3 arguments` {
+		t.Error("got message: ", got)
+	}
+}
+
 func Test_QueryRow_blob_null(t *testing.T) {
 	db, err := sql.Open("sqlite3", ":memory:")
 	if err != nil {
@@ -346,3 +633,604 @@ func Test_ZeroBlob(t *testing.T) {
 		t.Errorf(`got %q, want "\x00\x00\x00\x00"`, got)
 	}
 }
+
+func Test_NewConnector(t *testing.T) {
+	var opened int
+	db := sql.OpenDB(NewConnector(func() (*sqlite3.Conn, error) {
+		c, err := sqlite3.Open(":memory:")
+		if err != nil {
+			return nil, err
+		}
+		err = c.Exec(`PRAGMA user_version=42`)
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+		opened++
+		return c, nil
+	}))
+	defer db.Close()
+
+	var version int
+	err := db.QueryRow(`PRAGMA user_version`).Scan(&version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 42 {
+		t.Errorf("got %d, want 42", version)
+	}
+	if opened != 1 {
+		t.Errorf("got %d connections, want 1", opened)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Prepare_schemaChange(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`CREATE TABLE test (col); INSERT INTO test VALUES (1)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, err := db.Prepare(`SELECT * FROM test WHERE col = ?`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	var col int
+	err = stmt.QueryRow(1).Scan(&col)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec(`ALTER TABLE test ADD COLUMN other DEFAULT 2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The statement is transparently reprepared by SQLite,
+	// and picks up the new column.
+	var other int
+	err = stmt.QueryRow(1).Scan(&col, &other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if col != 1 || other != 2 {
+		t.Errorf("got (%d, %d), want (1, 2)", col, other)
+	}
+}
+
+func Test_Query_columns(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT 1 UNION ALL SELECT 2 UNION ALL SELECT 3`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var got []int
+	for rows.Next() {
+		// Columns must not advance the cursor.
+		if _, err := rows.Columns(); err != nil {
+			t.Fatal(err)
+		}
+		var i int
+		if err := rows.Scan(&i); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, i)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("got %v, want [1 2 3]", got)
+	}
+}
+
+func Test_BeginTx_readOnly(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The connection is writable after a read-only transaction.
+	_, err = db.Exec(`CREATE TABLE test (col)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Exec_result(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	check := func(res sql.Result, id, n int64) {
+		t.Helper()
+		if got, err := res.LastInsertId(); err != nil {
+			t.Fatal(err)
+		} else if got != id {
+			t.Errorf("got LastInsertId %d, want %d", got, id)
+		}
+		if got, err := res.RowsAffected(); err != nil {
+			t.Fatal(err)
+		} else if got != n {
+			t.Errorf("got RowsAffected %d, want %d", got, n)
+		}
+	}
+
+	// Multiple statements: values are those of the final statement.
+	res, err := db.Exec(`
+		CREATE TABLE test (id INTEGER PRIMARY KEY, col);
+		INSERT INTO test (col) VALUES (1), (2), (3);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(res, 3, 3)
+
+	res, err = db.Exec(`INSERT INTO test (col) VALUES (?)`, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(res, 4, 1)
+
+	res, err = db.Exec(`UPDATE test SET col = col * 2 WHERE col > ?`, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(res, 4, 3)
+
+	res, err = db.Exec(`DELETE FROM test WHERE col < ?`, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(res, 4, 2)
+}
+
+func Test_ResetSession(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`CREATE TABLE test (col)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = conn.ExecContext(ctx, `BEGIN; INSERT INTO test VALUES (1)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Return the connection with the transaction open.
+	conn.Close()
+
+	var count int
+	err = db.QueryRow(`SELECT count(*) FROM test`).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("got %d rows, want 0", count)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Query_noColumns(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, query := range []string{
+		`PRAGMA user_version=1`,
+		`PRAGMA foreign_keys=on`,
+		`CREATE TABLE test (col)`,
+		`INSERT INTO test VALUES (1)`,
+	} {
+		rows, err := db.Query(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cols, err := rows.Columns()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cols) != 0 {
+			t.Errorf("%s: got %v, want no columns", query, cols)
+		}
+		for rows.Next() {
+			t.Errorf("%s: want no rows", query)
+		}
+		if rows.NextResultSet() {
+			t.Errorf("%s: want no result sets", query)
+		}
+		if err := rows.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func Test_constraint(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE users (id INT PRIMARY KEY, email TEXT UNIQUE)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec(`INSERT INTO users VALUES (1, 'a@example.com')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec(`INSERT INTO users VALUES (?, ?)`, 2, "a@example.com")
+	if !errors.Is(err, sqlite3.CONSTRAINT_UNIQUE) {
+		t.Errorf("got %v, want sqlite3.CONSTRAINT_UNIQUE", err)
+	}
+	if !errors.Is(err, sqlite3.CONSTRAINT) {
+		t.Errorf("got %v, want sqlite3.CONSTRAINT", err)
+	}
+	var serr *sqlite3.Error
+	if !errors.As(err, &serr) {
+		t.Fatalf("got %T, want sqlite3.Error", err)
+	}
+	if rc := serr.ExtendedCode(); rc != sqlite3.CONSTRAINT_UNIQUE {
+		t.Errorf("got %d, want sqlite3.CONSTRAINT_UNIQUE", rc)
+	}
+}
+
+func Test_QueryRow_numbered(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// A bare ? follows the largest index so far: this is ?1, ?3, ?4.
+	var got [3]int
+	err = db.QueryRow(`SELECT ?, ?3, ?`, 1, 2, 3, 4).Scan(&got[0], &got[1], &got[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [3]int{1, 3, 4}; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	err = db.QueryRow(`SELECT ?, ?3, ?`, 1, 3, 4).Scan(&got[0], &got[1], &got[2])
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if got := err.Error(); got != `sqlite3: query "SELECT ?, ?3, ?" has 4 parameters [?1 ?2 ?3 ?4], got 3 arguments` {
+		t.Error("got message: ", got)
+	}
+}
+
+func Test_Rows_any(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	date := time.Date(2013, 10, 7, 4, 23, 19, 120_000_000, time.FixedZone("", -4*3600))
+
+	rows, err := db.Query(`
+		SELECT 1, 2.5, 'text', x'cafe', NULL, '2013-10-07 08:23:19', ?
+		UNION ALL
+		SELECT NULL, NULL, NULL, NULL, x'', NULL, NULL`, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	want := [][]any{
+		{int64(1), 2.5, "text", []byte{0xca, 0xfe}, nil, "2013-10-07 08:23:19", date},
+		{nil, nil, nil, nil, []byte{}, nil, nil},
+	}
+	for _, want := range want {
+		if !rows.Next() {
+			t.Fatal(rows.Err())
+		}
+
+		got := make([]any, len(want))
+		ptrs := make([]any, len(got))
+		for i := range got {
+			ptrs[i] = &got[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			t.Fatal(err)
+		}
+
+		for i := range want {
+			if w, ok := want[i].(time.Time); ok {
+				if g, ok := got[i].(time.Time); !ok || !g.Equal(w) {
+					t.Errorf("column %d: got %#v, want %v", i, got[i], w)
+				}
+				continue
+			}
+			if !reflect.DeepEqual(got[i], want[i]) {
+				t.Errorf("column %d: got %#v, want %#v", i, got[i], want[i])
+			}
+		}
+	}
+	if rows.Next() {
+		t.Error("want no more rows")
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// With _time_raw, time text is returned as stored.
+	db, err = sql.Open("sqlite3", "file::memory:?_time_raw=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var got any
+	err = db.QueryRow(`SELECT ?`, date).Scan(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := date.Format(time.RFC3339Nano); got != want {
+		t.Errorf("got %#v, want %q", got, want)
+	}
+}
+
+func Test_Duration(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var got int64
+	err = db.QueryRow(`SELECT ?`, 90*time.Second).Scan(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(90 * time.Second); got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+}
+
+type color int
+
+func (c color) String() string { return [...]string{"red", "green", "blue"}[c] }
+
+type version [3]int
+
+func (v version) String() string { return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2]) }
+
+type point struct{ x, y int }
+
+func (p *point) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d,%d", p.x, p.y)), nil
+}
+
+func (p *point) String() string { return "ignored" }
+
+type valued struct{}
+
+func (valued) Value() (driver.Value, error) { return "value", nil }
+func (valued) String() string               { return "ignored" }
+
+func Test_Stringer(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var nilPoint *point
+	var got [4]sql.NullString
+	err = db.QueryRow(`SELECT ?, ?, ?, ?`, version{1, 2, 3}, &point{1, 2}, valued{}, nilPoint).
+		Scan(&got[0], &got[1], &got[2], &got[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [4]sql.NullString{
+		{String: "1.2.3", Valid: true},
+		{String: "1,2", Valid: true},
+		{String: "value", Valid: true},
+		{},
+	}
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Basic kinds are converted by kind, not with String.
+	var typ string
+	var val int
+	err = db.QueryRow(`SELECT typeof(?1), ?1`, color(2)).Scan(&typ, &val)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typ != "integer" || val != 2 {
+		t.Errorf("got %s %d, want integer 2", typ, val)
+	}
+}
+
+func Test_RowsAffected_conflict(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE test (id INTEGER PRIMARY KEY, name TEXT UNIQUE);
+		INSERT INTO test VALUES (1, 'one'), (2, 'two');
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		sql  string
+		args []any
+		want int64
+	}{
+		// Ignored rows are not counted.
+		{`INSERT OR IGNORE INTO test VALUES (3, 'three'), (2, 'dup')`, nil, 1},
+		{`UPDATE OR IGNORE test SET name = ? WHERE id IN (1, 3)`, []any{"four"}, 1},
+		// A replaced row counts once: the implicit delete is not counted.
+		{`INSERT OR REPLACE INTO test VALUES (?, ?)`, []any{1, "uno"}, 1},
+		{`REPLACE INTO test VALUES (2, 'due'), (4, 'tre')`, nil, 2},
+		{`UPDATE OR REPLACE test SET name = 'uno' WHERE id = ?`, []any{3}, 1},
+	}
+	for _, tt := range tests {
+		res, err := db.Exec(tt.sql, tt.args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := res.RowsAffected()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.sql, got, tt.want)
+		}
+	}
+
+	var count int
+	err = db.QueryRow(`SELECT count(*) FROM test`).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("got %d rows, want 3", count)
+	}
+}
+
+func Test_routing(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// A command runs, even if its rows are never read.
+	rows, err := db.Query(`CREATE TABLE test (id INTEGER PRIMARY KEY, col)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+
+	stmt, err := db.Prepare(`INSERT INTO test (col) VALUES (?)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	rows, err = stmt.Query("query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+
+	// Errors are reported by Query.
+	_, err = db.Query(`INSERT INTO test (id) VALUES (1)`)
+	if err == nil {
+		t.Error("want error")
+	}
+
+	// RETURNING writes when executed.
+	res, err := db.Exec(`INSERT INTO test (col) VALUES (?) RETURNING id`, "exec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Errorf("got %d, want 1", n)
+	}
+
+	// RETURNING returns rows when queried.
+	var id int
+	err = db.QueryRow(`INSERT INTO test (col) VALUES (?) RETURNING id`, "returning").Scan(&id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 3 {
+		t.Errorf("got %d, want 3", id)
+	}
+
+	var got []string
+	rows, err = db.Query(`SELECT col FROM test ORDER BY id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, s)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"query", "exec", "returning"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
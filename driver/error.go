@@ -7,5 +7,6 @@ func (e errorString) Error() string { return string(e) }
 const (
 	assertErr    = errorString("sqlite3: assertion failed")
 	tailErr      = errorString("sqlite3: multiple statements")
+	paramsErr    = errorString("sqlite3: only the first statement can have parameters")
 	isolationErr = errorString("sqlite3: unsupported isolation level")
 )
@@ -1,15 +1,38 @@
 package driver
 
 import (
+	"database/sql"
 	"database/sql/driver"
+	"fmt"
 	"time"
+
+	"github.com/ncruces/go-sqlite3"
 )
 
-// Convert a string in [time.RFC3339Nano] format into a [time.Time]
+// Convert a string in format into a [time.Time]
 // if it roundtrips back to the same string.
 // This way times can be persisted to, and recovered from, the database,
 // but if a string is needed, [database/sql] will recover the same string.
-func maybeTime(text string) driver.Value {
+//
+// [sqlite3.TimeFormatDefault] and [sqlite3.TimeFormatAuto] use [time.RFC3339Nano].
+// Numeric formats never convert text.
+func maybeTime(text string, format sqlite3.TimeFormat) driver.Value {
+	switch format {
+	case sqlite3.TimeFormatDefault, sqlite3.TimeFormatAuto:
+	case
+		sqlite3.TimeFormatJulianDay,
+		sqlite3.TimeFormatUnix, sqlite3.TimeFormatUnixFrac,
+		sqlite3.TimeFormatUnixMilli, sqlite3.TimeFormatUnixMicro,
+		sqlite3.TimeFormatUnixNano:
+		return text
+	default:
+		date, err := format.Decode(text)
+		if err == nil && format.Encode(date) == text {
+			return date
+		}
+		return text
+	}
+
 	// Weed out (some) values that can't possibly be
 	// [time.RFC3339Nano] timestamps.
 	if len(text) < len("2006-01-02T15:04:05Z") {
@@ -29,3 +52,81 @@ func maybeTime(text string) driver.Value {
 	}
 	return text
 }
+
+// checkTimeFormat validates a _time_format DSN parameter:
+// one of the named formats, or a Go layout (using the reference time)
+// that can round-trip a time value.
+func checkTimeFormat(format sqlite3.TimeFormat) error {
+	switch format {
+	case
+		sqlite3.TimeFormatDefault, sqlite3.TimeFormatAuto,
+		sqlite3.TimeFormatJulianDay,
+		sqlite3.TimeFormatUnix, sqlite3.TimeFormatUnixFrac,
+		sqlite3.TimeFormatUnixMilli, sqlite3.TimeFormatUnixMicro,
+		sqlite3.TimeFormatUnixNano:
+		return nil
+	}
+
+	layout := string(format)
+	// Every field differs from the reference time,
+	// so a layout without any elements formats as itself.
+	sample := time.Date(2013, 10, 8, 16, 23, 19, 987654321, time.FixedZone("", 3600))
+	text := sample.Format(layout)
+	if text != layout {
+		t, err := time.Parse(layout, text)
+		if err == nil && t.Format(layout) == text {
+			return nil
+		}
+	}
+	return fmt.Errorf("sqlite3: invalid _time_format: %q can't round-trip a time value", layout)
+}
+
+// TimeScanner returns a [sql.Scanner] that decodes a time value into dest,
+// which must be a *time.Time or a *[sql.NullTime], using format,
+// or [sqlite3.TimeFormatAuto] if format is [sqlite3.TimeFormatDefault].
+//
+// The driver can't know the destination of a scan,
+// so text and numeric time values are only converted to [time.Time]
+// if they're wrapped with a TimeScanner:
+//
+//	var t sql.NullTime
+//	err := db.QueryRow(`SELECT unixepoch()`).Scan(driver.TimeScanner(&t, ""))
+//
+// NULL is scanned as the zero [time.Time], or leaves Valid false.
+func TimeScanner(dest any, format sqlite3.TimeFormat) sql.Scanner {
+	if format == sqlite3.TimeFormatDefault {
+		format = sqlite3.TimeFormatAuto
+	}
+	return timeScanner{dest, format}
+}
+
+type timeScanner struct {
+	dest   any
+	format sqlite3.TimeFormat
+}
+
+func (s timeScanner) Scan(src any) (err error) {
+	var t time.Time
+	switch v := src.(type) {
+	case nil:
+	case time.Time:
+		t = v
+	case []byte:
+		t, err = s.format.Decode(string(v))
+	default:
+		t, err = s.format.Decode(v)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch d := s.dest.(type) {
+	case *time.Time:
+		*d = t
+	case *sql.NullTime:
+		d.Time, d.Valid = t, src != nil
+	default:
+		return fmt.Errorf("sqlite3: unsupported time destination %T", s.dest)
+	}
+	return nil
+}
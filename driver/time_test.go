@@ -1,8 +1,11 @@
 package driver
 
 import (
+	"database/sql"
 	"testing"
 	"time"
+
+	"github.com/ncruces/go-sqlite3"
 )
 
 // This checks that any string can be recovered as the same string.
@@ -22,7 +25,7 @@ func Fuzz_maybeTime_1(f *testing.F) {
 	f.Add("Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua.")
 
 	f.Fuzz(func(t *testing.T, str string) {
-		value := maybeTime(str)
+		value := maybeTime(str, sqlite3.TimeFormatDefault)
 
 		switch v := value.(type) {
 		case time.Time:
@@ -59,7 +62,7 @@ func Fuzz_maybeTime_2(f *testing.F) {
 	f.Add(-763421161058, 222_222_222) // twosday, year 22222BC
 
 	checkTime := func(t *testing.T, date time.Time) {
-		value := maybeTime(date.Format(time.RFC3339Nano))
+		value := maybeTime(date.Format(time.RFC3339Nano), sqlite3.TimeFormatDefault)
 
 		switch v := value.(type) {
 		case time.Time:
@@ -98,3 +101,130 @@ func Fuzz_maybeTime_2(f *testing.F) {
 		checkTime(t, unix.In(time.FixedZone("", +8*3600)))
 	})
 }
+
+func TestTimeScanner(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	reference := time.Date(2013, 10, 7, 4, 23, 19, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"unixepoch", `SELECT unixepoch('2013-10-07 04:23:19')`},
+		{"julianday", `SELECT julianday('2013-10-07 04:23:19')`},
+		{"datetime", `SELECT datetime('2013-10-07 04:23:19')`},
+		{"rfc3339", `SELECT '2013-10-07T04:23:19Z'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got time.Time
+			err := db.QueryRow(tt.query).Scan(TimeScanner(&got, ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Sub(reference).Abs() > time.Millisecond {
+				t.Errorf("got %v, want %v", got, reference)
+			}
+
+			var null sql.NullTime
+			err = db.QueryRow(tt.query).Scan(TimeScanner(&null, ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !null.Valid || null.Time.Sub(reference).Abs() > time.Millisecond {
+				t.Errorf("got %v, want %v", null, reference)
+			}
+		})
+	}
+
+	null := sql.NullTime{Valid: true}
+	err = db.QueryRow(`SELECT NULL`).Scan(TimeScanner(&null, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if null.Valid {
+		t.Errorf("got %v, want NULL", null)
+	}
+
+	var got time.Time
+	err = db.QueryRow(`SELECT 'abc'`).Scan(TimeScanner(&got, ""))
+	if err == nil {
+		t.Error("want error")
+	}
+}
+
+func Test_checkTimeFormat(t *testing.T) {
+	tests := []struct {
+		format sqlite3.TimeFormat
+		valid  bool
+	}{
+		{sqlite3.TimeFormatDefault, true},
+		{sqlite3.TimeFormatAuto, true},
+		{sqlite3.TimeFormatUnixMilli, true},
+		{sqlite3.TimeFormat3, true},
+		{"2006/01/02 15:04:05.000000", true},
+		{"Jan _2 2006 3:04PM", true},
+		{"garbage", false},
+		{"Mon", false},
+	}
+	for _, tt := range tests {
+		err := checkTimeFormat(tt.format)
+		if valid := err == nil; valid != tt.valid {
+			t.Errorf("%q: got %v", tt.format, err)
+		}
+	}
+}
+
+func Test_timeFormat(t *testing.T) {
+	const layout = "2006/01/02 15:04:05"
+
+	db, err := sql.Open("sqlite3", "file::memory:?_time_format="+layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	date := time.Date(2013, 10, 7, 4, 23, 19, 0, time.UTC)
+
+	var stored bool
+	var scanned time.Time
+	err = db.QueryRow(`SELECT ? = '2013/10/07 04:23:19', ?`, date, date).Scan(&stored, &scanned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stored {
+		t.Errorf("want time stored with layout %q", layout)
+	}
+	if !scanned.Equal(date) {
+		t.Errorf("got %v, want %v", scanned, date)
+	}
+
+	// Text that doesn't round-trip stays text.
+	var other any
+	err = db.QueryRow(`SELECT '2013-10-07T04:23:19Z'`).Scan(&other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := other.(string); !ok {
+		t.Errorf("got %T, want string", other)
+	}
+
+	db, err = sql.Open("sqlite3", "file::memory:?_time_format=garbage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Ping()
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if got := err.Error(); got != `sqlite3: invalid _time_format: "garbage" can't round-trip a time value` {
+		t.Error("got message: ", got)
+	}
+}
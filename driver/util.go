@@ -1,6 +1,11 @@
 package driver
 
-import "database/sql/driver"
+import (
+	"database/sql/driver"
+
+	"github.com/ncruces/go-sqlite3"
+	"github.com/ncruces/go-sqlite3/internal/util"
+)
 
 func namedValues(args []driver.Value) []driver.NamedValue {
 	named := make([]driver.NamedValue, len(args))
@@ -12,3 +17,63 @@ func namedValues(args []driver.Value) []driver.NamedValue {
 	}
 	return named
 }
+
+// Find the indexes of the parameters that match a [database/sql.Named] value,
+// with any of the :name, @name and $name prefixes.
+func namedIndexes(ids []int, names []string, name string) []int {
+	for i, n := range names {
+		if len(n) == len(name)+1 && n[1:] == name {
+			switch n[0] {
+			case ':', '@', '$':
+				ids = append(ids, i+1)
+			}
+		}
+	}
+	return ids
+}
+
+// Split a comma-separated list of pragmas,
+// ignoring commas inside parentheses.
+func splitPragmas(s string) []string {
+	var res []string
+	var depth, start int
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				res = append(res, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(res, s[start:])
+}
+
+// Run the statements in tail, in order, stopping at the first error.
+// Arguments are only bound to the first statement in a query,
+// so statements in tail can't have parameters.
+func execTail(c *sqlite3.Conn, tail string) error {
+	for !util.EmptyStatement(tail) {
+		s, rest, err := c.Prepare(tail)
+		if err != nil || s == nil {
+			return err
+		}
+		if s.BindCount() != 0 {
+			s.Close()
+			return paramsErr
+		}
+		err = s.Exec()
+		if cerr := s.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		tail = rest
+	}
+	return nil
+}
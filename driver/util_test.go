@@ -16,3 +16,26 @@ func Test_namedValues(t *testing.T) {
 		t.Errorf("got %v, want %v", got, want)
 	}
 }
+
+func Test_namedIndexes(t *testing.T) {
+	names := []string{"", "?2", ":AAA", "@AAA", "$AAA", ":AA", "#AAA"}
+	want := []int{3, 4, 5}
+	got := namedIndexes(nil, names, "AAA")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := namedIndexes(nil, names, "BBB"); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
+
+func Test_splitPragmas(t *testing.T) {
+	want := []string{"foreign_keys(on)", "busy_timeout(5000)", "optimize(0x10002)"}
+	got := splitPragmas("foreign_keys(on),busy_timeout(5000),optimize(0x10002)")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := splitPragmas("busy_timeout=1000"); !reflect.DeepEqual(got, []string{"busy_timeout=1000"}) {
+		t.Errorf("got %v", got)
+	}
+}
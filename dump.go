@@ -0,0 +1,175 @@
+package sqlite3
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Dump writes the contents of a database to w, as an SQL script,
+// in the format of the sqlite3 command line shell .dump command.
+// If schema is empty, "main" is dumped.
+//
+// The script creates the tables and inserts their rows,
+// then creates indexes, triggers and views, all in a single transaction.
+// Values are written as SQL literals, see [QuoteString] and [BlobLiteral].
+// Generated columns are not written, and rowids are not preserved.
+// Use [Conn.Load] to run the script.
+//
+// https://www.sqlite.org/cli.html#converting_an_entire_database_to_a_text_file
+func (c *Conn) Dump(w io.Writer, schema string) error {
+	if schema == "" {
+		schema = "main"
+	}
+	qschema := QuoteIdentifier(schema)
+
+	type object struct{ name, sql string }
+	var tables, others []object
+
+	query := `SELECT name, type, sql FROM ` + qschema + `.sqlite_schema WHERE sql NOT NULL ORDER BY rowid`
+	err := c.Query(query, nil, func(stmt *Stmt) error {
+		obj := object{stmt.ColumnText(0), stmt.ColumnText(2)}
+		if stmt.ColumnText(1) == "table" {
+			tables = append(tables, obj)
+		} else {
+			others = append(others, obj)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, "PRAGMA foreign_keys=OFF;\nBEGIN TRANSACTION;\n"); err != nil {
+		return err
+	}
+
+	var sequence bool
+	for _, t := range tables {
+		switch {
+		case t.name == "sqlite_sequence":
+			// Created automatically, for AUTOINCREMENT tables.
+			sequence = true
+			continue
+		case strings.HasPrefix(t.name, "sqlite_"):
+			continue
+		}
+		if _, err := io.WriteString(w, t.sql+";\n"); err != nil {
+			return err
+		}
+		if strings.HasPrefix(strings.ToUpper(t.sql), "CREATE VIRTUAL TABLE") {
+			continue
+		}
+		if err := c.dumpRows(w, schema, t.name); err != nil {
+			return err
+		}
+	}
+
+	if sequence {
+		if _, err := io.WriteString(w, "DELETE FROM sqlite_sequence;\n"); err != nil {
+			return err
+		}
+		if err := c.dumpRows(w, schema, "sqlite_sequence"); err != nil {
+			return err
+		}
+	}
+
+	for _, o := range others {
+		if strings.HasPrefix(o.name, "sqlite_") {
+			continue
+		}
+		if _, err := io.WriteString(w, o.sql+";\n"); err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(w, "COMMIT;\n")
+	return err
+}
+
+// dumpRows writes an INSERT statement for each row of a table.
+func (c *Conn) dumpRows(w io.Writer, schema, table string) error {
+	info, err := c.TableInfo(schema, table)
+	if err != nil {
+		return err
+	}
+
+	var cols []string
+	for _, col := range info.Columns {
+		// Skip generated (and hidden) columns.
+		if col.Hidden == 0 {
+			cols = append(cols, QuoteIdentifier(col.Name))
+		}
+	}
+	if len(cols) == 0 {
+		return nil
+	}
+
+	insert := "INSERT INTO " + QuoteIdentifier(table)
+	if len(cols) != len(info.Columns) {
+		insert += "(" + strings.Join(cols, ",") + ")"
+	}
+	insert += " VALUES("
+
+	query := "SELECT " + strings.Join(cols, ",") +
+		" FROM " + QuoteIdentifier(schema) + "." + QuoteIdentifier(table)
+
+	var buf []byte
+	return c.Query(query, nil, func(stmt *Stmt) error {
+		buf = append(buf[:0], insert...)
+		for i := range cols {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendLiteral(buf, stmt, i)
+		}
+		buf = append(buf, ");\n"...)
+		_, err := w.Write(buf)
+		return err
+	})
+}
+
+// appendLiteral appends the value of the result column as an SQL literal.
+func appendLiteral(buf []byte, stmt *Stmt, col int) []byte {
+	switch stmt.ColumnType(col) {
+	case INTEGER:
+		return strconv.AppendInt(buf, stmt.ColumnInt64(col), 10)
+	case FLOAT:
+		f := stmt.ColumnFloat(col)
+		switch {
+		case math.IsInf(f, +1):
+			return append(buf, "1e999"...)
+		case math.IsInf(f, -1):
+			return append(buf, "-1e999"...)
+		}
+		n := len(buf)
+		buf = strconv.AppendFloat(buf, f, 'g', -1, 64)
+		// Keep the value a REAL when read back.
+		if !strings.ContainsAny(string(buf[n:]), ".e") {
+			buf = append(buf, ".0"...)
+		}
+		return buf
+	case TEXT:
+		return append(buf, QuoteString(stmt.ColumnText(col))...)
+	case BLOB:
+		return append(buf, BlobLiteral(stmt.ColumnRawBlob(col))...)
+	default:
+		return append(buf, "NULL"...)
+	}
+}
+
+// Load reads an SQL script from r, like one written by [Conn.Dump],
+// and runs it with [Conn.ExecScript].
+// If the script fails within a transaction, the transaction is rolled back.
+func (c *Conn) Load(r io.Reader) error {
+	script, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	_, err = c.ExecScript(string(script))
+	if err != nil && !c.GetAutocommit() {
+		c.Exec(`ROLLBACK`)
+	}
+	return err
+}
@@ -1,6 +1,7 @@
 package sqlite3
 
 import (
+	"errors"
 	"runtime"
 	"strconv"
 	"strings"
@@ -14,6 +15,8 @@ type Error struct {
 	str  string
 	msg  string
 	sql  string
+
+	offset int
 }
 
 // Code returns the primary error code for this error.
@@ -50,16 +53,112 @@ func (e *Error) Error() string {
 	return b.String()
 }
 
+// Is tests whether this error matches a given [ErrorCode] or [ExtendedErrorCode].
+//
+// It makes it possible to do:
+//
+//	if errors.Is(err, sqlite3.BUSY) {
+//		// ... handle BUSY
+//	}
+func (e *Error) Is(err error) bool {
+	switch c := err.(type) {
+	case ErrorCode:
+		return c == e.Code()
+	case ExtendedErrorCode:
+		return c == e.ExtendedCode()
+	}
+	return false
+}
+
 // Temporary returns true for [BUSY] errors.
 func (e *Error) Temporary() bool {
 	return e.Code() == BUSY
 }
 
-// SQL returns the SQL starting at the token that triggered a syntax error.
+// SQL returns the SQL starting at the token that triggered a syntax error,
+// or the statement that failed, for errors returned by [Conn.ExecScript].
 func (e *Error) SQL() string {
 	return e.sql
 }
 
+// Offset returns the byte offset of the statement that failed
+// in the script passed to [Conn.ExecScript].
+// It returns 0 for other errors.
+func (e *Error) Offset() int {
+	return e.offset
+}
+
+// Error implements the error interface, so that codes can be used
+// as targets for [errors.Is], see [Error.Is].
+func (e ErrorCode) Error() string {
+	var msg string
+	if int(e) < len(errorCodeStr) {
+		msg = errorCodeStr[e]
+	}
+	if msg == "" {
+		msg = "unknown error"
+	}
+	return "sqlite3: " + msg
+}
+
+// Error implements the error interface, so that codes can be used
+// as targets for [errors.Is], see [Error.Is].
+// The message is that of the primary result code.
+func (e ExtendedErrorCode) Error() string {
+	return ErrorCode(e).Error()
+}
+
+// errorCodeStr mirrors the messages of sqlite3_errstr.
+//
+// https://www.sqlite.org/c3ref/errcode.html
+var errorCodeStr = [...]string{
+	ERROR:      "SQL logic error",
+	PERM:       "access permission denied",
+	ABORT:      "query aborted",
+	BUSY:       "database is locked",
+	LOCKED:     "database table is locked",
+	NOMEM:      "out of memory",
+	READONLY:   "attempt to write a readonly database",
+	INTERRUPT:  "interrupted",
+	IOERR:      "disk I/O error",
+	CORRUPT:    "database disk image is malformed",
+	NOTFOUND:   "unknown operation",
+	FULL:       "database or disk is full",
+	CANTOPEN:   "unable to open database file",
+	PROTOCOL:   "locking protocol",
+	SCHEMA:     "database schema has changed",
+	TOOBIG:     "string or blob too big",
+	CONSTRAINT: "constraint failed",
+	MISMATCH:   "datatype mismatch",
+	MISUSE:     "bad parameter or other API misuse",
+	AUTH:       "authorization denied",
+	RANGE:      "column index out of range",
+	NOTADB:     "file is not a database",
+	NOTICE:     "notification message",
+	WARNING:    "warning message",
+}
+
+// interruptError is returned when an operation fails after its context is done.
+// It matches the context error with [errors.Is],
+// and unwraps to the error that SQLite returned, usually an [*Error]
+// with the [INTERRUPT] code.
+type interruptError struct {
+	ctx error
+	err error
+}
+
+func (e *interruptError) Error() string {
+	return e.ctx.Error() + ": " + e.err.Error()
+}
+
+func (e *interruptError) Unwrap() error {
+	return e.err
+}
+
+func (e *interruptError) Is(err error) bool {
+	return errors.Is(e.ctx, err)
+}
+
 type errorString string
 
 func (e errorString) Error() string { return string(e) }
@@ -74,6 +173,9 @@ const (
 	noFuncErr   = errorString("sqlite3: could not find function: ")
 	timeErr     = errorString("sqlite3: invalid time value")
 	notImplErr  = errorString("sqlite3: not implemented")
+	blobLitErr  = errorString("sqlite3: invalid blob literal")
+	tailErr     = errorString("sqlite3: multiple statements")
+	poolErr     = errorString("sqlite3: pool is closed")
 )
 
 func assertErr() errorString {
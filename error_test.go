@@ -1,6 +1,7 @@
 package sqlite3
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -20,7 +21,35 @@ func TestError(t *testing.T) {
 
 func Test_assertErr(t *testing.T) {
 	err := assertErr()
-	if s := err.Error(); !strings.HasPrefix(s, "sqlite3: assertion failed") || !strings.HasSuffix(s, "error_test.go:22)") {
+	if s := err.Error(); !strings.HasPrefix(s, "sqlite3: assertion failed") || !strings.HasSuffix(s, "error_test.go:23)") {
+		t.Errorf("got %q", s)
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	err := &Error{code: uint64(CONSTRAINT_UNIQUE)}
+	if !errors.Is(err, CONSTRAINT) {
+		t.Error("want CONSTRAINT")
+	}
+	if !errors.Is(err, CONSTRAINT_UNIQUE) {
+		t.Error("want CONSTRAINT_UNIQUE")
+	}
+	if errors.Is(err, CONSTRAINT_PRIMARYKEY) {
+		t.Error("want not CONSTRAINT_PRIMARYKEY")
+	}
+	if errors.Is(err, BUSY) {
+		t.Error("want not BUSY")
+	}
+}
+
+func TestErrorCode_Error(t *testing.T) {
+	if s := BUSY.Error(); s != "sqlite3: database is locked" {
+		t.Errorf("got %q", s)
+	}
+	if s := CONSTRAINT_UNIQUE.Error(); s != "sqlite3: constraint failed" {
+		t.Errorf("got %q", s)
+	}
+	if s := ErrorCode(99).Error(); s != "sqlite3: unknown error" {
 		t.Errorf("got %q", s)
 	}
 }
@@ -0,0 +1,45 @@
+// Package util holds helpers shared by the sqlite3 and driver packages.
+package util
+
+import "strings"
+
+// SkipSpace returns sql with leading whitespace and comments removed.
+// An unterminated block comment extends to the end of sql.
+func SkipSpace(sql string) string {
+	for {
+		sql = strings.TrimLeft(sql, " \n\r\t\v\f")
+		switch {
+		case strings.HasPrefix(sql, "--"):
+			if i := strings.IndexByte(sql, '\n'); i >= 0 {
+				sql = sql[i+1:]
+			} else {
+				return ""
+			}
+		case strings.HasPrefix(sql, "/*"):
+			if i := strings.Index(sql[2:], "*/"); i >= 0 {
+				sql = sql[i+4:]
+			} else {
+				return ""
+			}
+		default:
+			return sql
+		}
+	}
+}
+
+// EmptyStatement returns true if stmt is an empty SQL statement:
+// only whitespace, comments and semicolons.
+// This is used as an optimization.
+// It's OK to always return false here.
+func EmptyStatement(stmt string) bool {
+	for {
+		stmt = SkipSpace(stmt)
+		if stmt == "" {
+			return true
+		}
+		if stmt[0] != ';' {
+			return false
+		}
+		stmt = stmt[1:]
+	}
+}
@@ -0,0 +1,32 @@
+package util
+
+import "testing"
+
+func TestEmptyStatement(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		stmt string
+		want bool
+	}{
+		{"empty", "", true},
+		{"space", " ", true},
+		{"separator", ";\n ", true},
+		{"line comment", "-- comment", true},
+		{"block comment", "/* comment */;", true},
+		{"unterminated", "; /* comment", true},
+		{"comments", "-- one\n/* two */ -- three\n;", true},
+		{"begin", "BEGIN", false},
+		{"select", "SELECT 1;", false},
+		{"commented", "/* comment */ SELECT 1", false},
+		{"divide", "/ 2", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EmptyStatement(tt.stmt); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package sqlite3
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Pool is a fixed size pool of connections to the same database.
+//
+// Each connection is an independent instance of the compiled SQLite module,
+// so connections can be used concurrently from different goroutines.
+// Connections are opened lazily, as needed.
+type Pool struct {
+	// Init, if set, is called on each newly opened connection,
+	// e.g. to set pragmas. It must be set before the first call to Get.
+	// Init runs after PRAGMA locking_mode=normal, see [NewPool].
+	Init func(*Conn) error
+
+	filename string
+	tokens   chan struct{}
+
+	mtx    sync.Mutex
+	idle   []*Conn
+	closed bool
+}
+
+// NewPool creates a pool of at most size connections to filename,
+// opened with [Open].
+//
+// The embedded SQLite defaults to locking_mode=exclusive,
+// so a second connection to a database file would fail with [BUSY].
+// Each connection executes PRAGMA locking_mode=normal when opened,
+// before [Pool.Init].
+func NewPool(filename string, size int) (*Pool, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("sqlite3: invalid pool size: %d", size)
+	}
+	if err := sqlite3.compile(context.Background()); err != nil {
+		return nil, err
+	}
+	return &Pool{
+		filename: filename,
+		tokens:   make(chan struct{}, size),
+	}, nil
+}
+
+// Get returns a connection from the pool, opening a new one if none are idle.
+// If size connections are in use, Get blocks until one is returned with [Pool.Put].
+func (p *Pool) Get() (*Conn, error) {
+	p.tokens <- struct{}{}
+
+	p.mtx.Lock()
+	if p.closed {
+		p.mtx.Unlock()
+		<-p.tokens
+		return nil, poolErr
+	}
+	if n := len(p.idle); n > 0 {
+		c := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mtx.Unlock()
+		return c, nil
+	}
+	p.mtx.Unlock()
+
+	c, err := Open(p.filename)
+	if err == nil {
+		err = c.Exec(`PRAGMA locking_mode=normal`)
+		if err != nil {
+			c.Close()
+		}
+	}
+	if err == nil && p.Init != nil {
+		err = p.Init(c)
+		if err != nil {
+			c.Close()
+		}
+	}
+	if err != nil {
+		<-p.tokens
+		return nil, err
+	}
+	return c, nil
+}
+
+// Put returns a connection obtained from [Pool.Get] to the pool.
+// Any open transaction is rolled back, and the interrupt context is cleared.
+// Connections that can't be reset, or are returned after the pool is closed, are closed.
+func (p *Pool) Put(c *Conn) {
+	defer func() { <-p.tokens }()
+
+	c.SetInterrupt(context.Background())
+	if !c.GetAutocommit() && c.Exec(`ROLLBACK`) != nil {
+		c.Close()
+		return
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.closed {
+		c.Close()
+		return
+	}
+	p.idle = append(p.idle, c)
+}
+
+// Close closes all idle connections in the pool.
+// Connections in use are closed when they are returned with [Pool.Put],
+// and subsequent calls to [Pool.Get] fail.
+func (p *Pool) Close() error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	var err error
+	for _, c := range p.idle {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	p.idle = nil
+	p.closed = true
+	return err
+}
@@ -0,0 +1,48 @@
+package sqlite3
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// BlobLiteral returns b as an SQL BLOB literal, like x'cafe'.
+//
+// https://www.sqlite.org/lang_expr.html#literal_values_constants_
+func BlobLiteral(b []byte) string {
+	buf := make([]byte, 2+hex.EncodedLen(len(b))+1)
+	buf[0] = 'x'
+	buf[1] = '\''
+	hex.Encode(buf[2:], b)
+	buf[len(buf)-1] = '\''
+	return string(buf)
+}
+
+// ParseBlobLiteral parses an SQL BLOB literal, like x'cafe' or X'CAFE'.
+//
+// https://www.sqlite.org/lang_expr.html#literal_values_constants_
+func ParseBlobLiteral(s string) ([]byte, error) {
+	if len(s) < 3 || (s[0] != 'x' && s[0] != 'X') || s[1] != '\'' || s[len(s)-1] != '\'' {
+		return nil, blobLitErr
+	}
+	b, err := hex.DecodeString(s[2 : len(s)-1])
+	if err != nil {
+		return nil, blobLitErr
+	}
+	return b, nil
+}
+
+// QuoteIdentifier returns s as an SQL identifier, like "name",
+// doubling any embedded double quotes.
+//
+// https://www.sqlite.org/lang_keywords.html
+func QuoteIdentifier(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// QuoteString returns s as an SQL string literal, like 'text',
+// doubling any embedded single quotes.
+//
+// https://www.sqlite.org/lang_expr.html#literal_values_constants_
+func QuoteString(s string) string {
+	return `'` + strings.ReplaceAll(s, `'`, `''`) + `'`
+}
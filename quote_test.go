@@ -0,0 +1,86 @@
+package sqlite3
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlobLiteral(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		blob []byte
+		want string
+	}{
+		{nil, "x''"},
+		{[]byte{0xca, 0xfe}, "x'cafe'"},
+		{[]byte("\x00\xff"), "x'00ff'"},
+	}
+	for _, tt := range tests {
+		got := BlobLiteral(tt.blob)
+		if got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+		back, err := ParseBlobLiteral(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(back, tt.blob) {
+			t.Errorf("got %x, want %x", back, tt.blob)
+		}
+	}
+}
+
+func TestParseBlobLiteral(t *testing.T) {
+	t.Parallel()
+
+	if got, err := ParseBlobLiteral("X'CAFE'"); err != nil || string(got) != "\xca\xfe" {
+		t.Errorf("got %x, %v", got, err)
+	}
+
+	for _, s := range []string{"", "x", "x'", "'cafe'", "x'caf'", "x'cafg'", "y'cafe'", "x'cafe"} {
+		if _, err := ParseBlobLiteral(s); err == nil {
+			t.Errorf("%q: want error", s)
+		}
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ident string
+		want  string
+	}{
+		{"", `""`},
+		{"name", `"name"`},
+		{`say "hi"`, `"say ""hi"""`},
+		{"new\nline", "\"new\nline\""},
+		{`back\slash`, `"back\slash"`},
+	}
+	for _, tt := range tests {
+		if got := QuoteIdentifier(tt.ident); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestQuoteString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		str  string
+		want string
+	}{
+		{"", `''`},
+		{"text", `'text'`},
+		{"it's", `'it''s'`},
+		{"new\nline", "'new\nline'"},
+		{`'); DROP TABLE users; --`, `'''); DROP TABLE users; --'`},
+	}
+	for _, tt := range tests {
+		if got := QuoteString(tt.str); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package sqlite3
+
+import "fmt"
+
+// TableInfo describes a table, see [Conn.TableInfo].
+type TableInfo struct {
+	Schema string
+	Name   string
+	Type   string // table, view, virtual or shadow.
+
+	WithoutRowID bool
+	Strict       bool
+
+	Columns []ColumnInfo
+}
+
+// Virtual reports whether this is a virtual table.
+func (t *TableInfo) Virtual() bool {
+	return t.Type == "virtual"
+}
+
+// ColumnInfo describes a column of a table, see [Conn.TableInfo].
+type ColumnInfo struct {
+	Name    string
+	Type    string // Declared type; for STRICT tables, one of the strict types.
+	NotNull bool
+	Default string // SQL text of the default value, or empty.
+	PK      int    // 1-based index in the primary key, or 0.
+	Hidden  int    // 0 for normal columns, nonzero for hidden and generated columns.
+}
+
+// TableInfo returns information about a table, view or virtual table,
+// using PRAGMA table_list and PRAGMA table_xinfo.
+// If schema is empty, all attached databases are searched.
+//
+// https://www.sqlite.org/pragma.html#pragma_table_list
+func (c *Conn) TableInfo(schema, table string) (*TableInfo, error) {
+	query := "PRAGMA table_list(" + QuoteString(table) + ");"
+	if schema != "" {
+		query = "PRAGMA " + QuoteIdentifier(schema) + ".table_list(" + QuoteString(table) + ");"
+	}
+	tables, err := c.tableList(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("sqlite3: no such table: %s", table)
+	}
+
+	info := &tables[0]
+	if err := c.tableColumns(info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Tables returns information about all tables, views and virtual tables
+// in a database, including the schema table (e.g. sqlite_schema),
+// using PRAGMA table_list and PRAGMA table_xinfo.
+// If schema is empty, all attached databases are included.
+//
+// https://www.sqlite.org/pragma.html#pragma_table_list
+func (c *Conn) Tables(schema string) ([]TableInfo, error) {
+	query := "PRAGMA table_list;"
+	if schema != "" {
+		query = "PRAGMA " + QuoteIdentifier(schema) + ".table_list;"
+	}
+	tables, err := c.tableList(query)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tables {
+		if err := c.tableColumns(&tables[i]); err != nil {
+			return nil, err
+		}
+	}
+	return tables, nil
+}
+
+func (c *Conn) tableList(query string) ([]TableInfo, error) {
+	var tables []TableInfo
+	err := c.Query(query, nil, func(stmt *Stmt) error {
+		tables = append(tables, TableInfo{
+			Schema:       stmt.ColumnText(0),
+			Name:         stmt.ColumnText(1),
+			Type:         stmt.ColumnText(2),
+			WithoutRowID: stmt.ColumnBool(4),
+			Strict:       stmt.ColumnBool(5),
+		})
+		return nil
+	})
+	return tables, err
+}
+
+func (c *Conn) tableColumns(info *TableInfo) error {
+	query := "PRAGMA " + QuoteIdentifier(info.Schema) + ".table_xinfo(" + QuoteString(info.Name) + ");"
+	return c.Query(query, nil, func(stmt *Stmt) error {
+		info.Columns = append(info.Columns, ColumnInfo{
+			Name:    stmt.ColumnText(1),
+			Type:    stmt.ColumnText(2),
+			NotNull: stmt.ColumnBool(3),
+			Default: stmt.ColumnText(4),
+			PK:      stmt.ColumnInt(5),
+			Hidden:  stmt.ColumnInt(6),
+		})
+		return nil
+	})
+}
+
+// Schemas returns the names of the databases on the connection,
+// in the order used by [Conn.SchemaName]:
+// "main", "temp", then any attached databases.
+//
+// https://www.sqlite.org/pragma.html#pragma_database_list
+func (c *Conn) Schemas() ([]string, error) {
+	names := []string{"main", "temp"}
+	err := c.Query(`PRAGMA database_list`, nil, func(stmt *Stmt) error {
+		seq := stmt.ColumnInt(0)
+		for len(names) <= seq {
+			names = append(names, "")
+		}
+		names[seq] = stmt.ColumnText(1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// SchemaName returns the name of the database with the given index:
+// 0 is "main", 1 is "temp", and higher indexes are attached databases.
+// It returns an empty string if index is out of range.
+//
+// https://www.sqlite.org/c3ref/db_name.html
+func (c *Conn) SchemaName(index int) string {
+	names, err := c.Schemas()
+	if err != nil || index < 0 || index >= len(names) {
+		return ""
+	}
+	return names[index]
+}
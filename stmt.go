@@ -1,10 +1,31 @@
 package sqlite3
 
 import (
+	"context"
+	"database/sql"
+	"encoding"
+	"fmt"
 	"math"
+	"net"
+	"net/netip"
+	"strings"
 	"time"
+	"unicode/utf16"
+
+	"github.com/ncruces/go-sqlite3/internal/util"
 )
 
+// StrictBindings makes [Stmt.Step] fail with [MISUSE]
+// if a statement that has finished executing is stepped again
+// without calling [Stmt.Reset] first,
+// and the Bind methods fail with MISUSE
+// if a statement is bound after a step, without a reset.
+//
+// Without a reset, SQLite silently starts over
+// with the existing bindings, which hides failed attempts to rebind them.
+// Enable this while debugging or testing.
+var StrictBindings bool
+
 // Stmt is a prepared statement object.
 //
 // https://www.sqlite.org/c3ref/stmt.html
@@ -12,6 +33,21 @@ type Stmt struct {
 	c      *Conn
 	handle uint32
 	err    error
+	row    bool
+	done   bool
+	code   uint64
+
+	// Interrupt context set by StepContext for the current run,
+	// and the connection's previous one, to restore.
+	ctx    context.Context
+	oldCtx context.Context
+
+	// Explain mode, parsed from the SQL text.
+	explain int
+
+	// Cached counts, or -1.
+	bindCount int
+	colCount  int
 }
 
 // Close destroys the prepared statement object.
@@ -24,6 +60,8 @@ func (s *Stmt) Close() error {
 		return nil
 	}
 
+	s.restoreInterrupt()
+	defer s.c.unlock(s.c.lock())
 	r, err := s.c.api.finalize.Call(s.c.ctx, uint64(s.handle))
 	if err != nil {
 		panic(err)
@@ -37,11 +75,16 @@ func (s *Stmt) Close() error {
 //
 // https://www.sqlite.org/c3ref/reset.html
 func (s *Stmt) Reset() error {
+	s.restoreInterrupt()
+	defer s.c.unlock(s.c.lock())
 	r, err := s.c.api.reset.Call(s.c.ctx, uint64(s.handle))
 	if err != nil {
 		panic(err)
 	}
 	s.err = nil
+	s.row = false
+	s.done = false
+	s.code = _OK
 	return s.c.error(r[0])
 }
 
@@ -49,6 +92,7 @@ func (s *Stmt) Reset() error {
 //
 // https://www.sqlite.org/c3ref/clear_bindings.html
 func (s *Stmt) ClearBindings() error {
+	defer s.c.unlock(s.c.lock())
 	r, err := s.c.api.clearBindings.Call(s.c.ctx, uint64(s.handle))
 	if err != nil {
 		panic(err)
@@ -56,6 +100,19 @@ func (s *Stmt) ClearBindings() error {
 	return s.c.error(r[0])
 }
 
+// ResetAndClear resets the prepared statement object,
+// and then resets all bindings on it, ready for reuse.
+// Both steps are always taken; the first error is returned.
+//
+// https://www.sqlite.org/c3ref/reset.html
+func (s *Stmt) ResetAndClear() error {
+	err := s.Reset()
+	if cerr := s.ClearBindings(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
 // Step evaluates the SQL statement.
 // If the SQL statement being executed returns any data,
 // then true is returned each time a new row of data is ready for processing by the caller.
@@ -66,15 +123,31 @@ func (s *Stmt) ClearBindings() error {
 //
 // https://www.sqlite.org/c3ref/step.html
 func (s *Stmt) Step() bool {
+	defer s.c.unlock(s.c.lock())
+	if s.done && StrictBindings {
+		s.err = s.misuse("statement stepped again without a reset")
+		s.code = uint64(MISUSE)
+		return false
+	}
 	s.c.checkInterrupt()
+	if !s.row {
+		// Starting the statement may reprepare it,
+		// which can change the number of columns.
+		s.colCount = -1
+	}
 	r, err := s.c.api.step.Call(s.c.ctx, uint64(s.handle))
 	if err != nil {
 		panic(err)
 	}
-	if r[0] == _ROW {
+	s.code = r[0]
+	s.row = r[0] == _ROW
+	if s.row {
+		s.c.lastErr = nil
 		return true
 	}
+	s.done = true
 	if r[0] == _DONE {
+		s.c.lastErr = nil
 		s.err = nil
 	} else {
 		s.err = s.c.error(r[0])
@@ -82,6 +155,106 @@ func (s *Stmt) Step() bool {
 	return false
 }
 
+// checkBind returns [MISUSE] if [StrictBindings] is set,
+// and the statement was stepped without a reset.
+func (s *Stmt) checkBind() error {
+	if StrictBindings && (s.row || s.done) {
+		return s.misuse("statement bound after a step without a reset")
+	}
+	return nil
+}
+
+// misuse records and returns a [MISUSE] error.
+func (s *Stmt) misuse(msg string) *Error {
+	err := &Error{
+		code: uint64(MISUSE),
+		str:  "bad parameter or other API misuse",
+		msg:  msg,
+	}
+	s.c.lastErr = err
+	return err
+}
+
+// LastCode returns the raw result code of the most recent call to [Stmt.Step]:
+// 100 (SQLITE_ROW) if a row is ready, 101 (SQLITE_DONE) if the statement
+// has finished executing, or the extended error code otherwise.
+// It returns 0 (SQLITE_OK) before the first call to Step, and after [Stmt.Reset].
+//
+// Unlike [Stmt.Err], which reports the same failures as an [*Error],
+// this allows e.g. distinguishing [BUSY_SNAPSHOT] or [BUSY_TIMEOUT]
+// without a type assertion.
+//
+// https://www.sqlite.org/c3ref/step.html
+func (s *Stmt) LastCode() ExtendedErrorCode {
+	return ExtendedErrorCode(s.code)
+}
+
+// StepRow calls [Stmt.Step], and returns true, nil if a row is ready,
+// false, nil if the statement has finished executing,
+// or false and the error, if one occurred:
+//
+//	for {
+//		ok, err := stmt.StepRow()
+//		if err != nil {
+//			return err
+//		}
+//		if !ok {
+//			break
+//		}
+//		// ... process the row
+//	}
+//
+// https://www.sqlite.org/c3ref/step.html
+func (s *Stmt) StepRow() (bool, error) {
+	if s.Step() {
+		return true, nil
+	}
+	return false, s.err
+}
+
+// StepContext is like [Stmt.StepRow], but interrupts the statement if ctx is done,
+// see [Conn.SetInterrupt].
+//
+// The interrupt context is set on the first call to StepContext,
+// and kept while stepping through the rows, so calling StepContext
+// for every row is as cheap as calling [Conn.SetInterrupt] once.
+// The connection's previous interrupt context is restored
+// when the statement finishes executing, fails, or is reset or closed.
+//
+// If the step fails after ctx is done, the returned error matches ctx.Err(),
+// i.e. [context.Canceled] or [context.DeadlineExceeded], with [errors.Is],
+// and still unwraps to the [*Error] returned by SQLite.
+//
+// https://www.sqlite.org/c3ref/interrupt.html
+func (s *Stmt) StepContext(ctx context.Context) (bool, error) {
+	if s.ctx != ctx {
+		old := s.c.SetInterrupt(ctx)
+		if s.ctx == nil {
+			s.oldCtx = old
+		}
+		s.ctx = ctx
+	}
+
+	if s.Step() {
+		return true, nil
+	}
+	s.restoreInterrupt()
+	if s.err != nil && ctx.Err() != nil {
+		return false, &interruptError{ctx: ctx.Err(), err: s.err}
+	}
+	return false, s.err
+}
+
+// restoreInterrupt restores the interrupt context
+// the connection had before [Stmt.StepContext].
+func (s *Stmt) restoreInterrupt() {
+	if s.ctx != nil {
+		s.c.SetInterrupt(s.oldCtx)
+		s.ctx = nil
+		s.oldCtx = nil
+	}
+}
+
 // Err gets the last error occurred during [Stmt.Step].
 // Err returns nil after [Stmt.Reset] is called.
 //
@@ -90,6 +263,53 @@ func (s *Stmt) Err() error {
 	return s.err
 }
 
+// Explain changes the explain setting of the prepared statement:
+// 0 for a normal statement, 1 for EXPLAIN, 2 for EXPLAIN QUERY PLAN.
+// The explain output is then read with the column accessors.
+//
+// Explain requires SQLite 3.43.0 or later,
+// and returns an error if the SQLite binary doesn't support it.
+// The embedded SQLite binary doesn't, so unless [Binary] does,
+// prepare the statement with EXPLAIN or EXPLAIN QUERY PLAN instead.
+//
+// https://www.sqlite.org/c3ref/stmt_explain.html
+func (s *Stmt) Explain(mode int) error {
+	if s.c.api.stmtExplain == nil {
+		return notImplErr
+	}
+	defer s.c.unlock(s.c.lock())
+	r, err := s.c.api.stmtExplain.Call(s.c.ctx,
+		uint64(s.handle), uint64(mode))
+	if err != nil {
+		panic(err)
+	}
+	if r[0] == _OK {
+		s.explain = mode
+	}
+	return s.c.error(r[0])
+}
+
+// IsExplain reports whether the prepared statement is an EXPLAIN statement:
+// 0 for a normal statement, 1 for EXPLAIN, 2 for EXPLAIN QUERY PLAN.
+//
+// The embedded SQLite binary doesn't export sqlite3_stmt_isexplain,
+// so unless [Binary] does, this is determined from the leading keywords
+// of the SQL text the statement was prepared with
+// (updated by [Stmt.Explain]).
+//
+// https://www.sqlite.org/c3ref/stmt_isexplain.html
+func (s *Stmt) IsExplain() int {
+	if s.c.api.stmtIsExplain == nil {
+		return s.explain
+	}
+	defer s.c.unlock(s.c.lock())
+	r, err := s.c.api.stmtIsExplain.Call(s.c.ctx, uint64(s.handle))
+	if err != nil {
+		panic(err)
+	}
+	return int(r[0])
+}
+
 // Exec is a convenience function that repeatedly calls [Stmt.Step] until it returns false,
 // then calls [Stmt.Reset] to reset the statement and get any error that occurred.
 func (s *Stmt) Exec() error {
@@ -102,12 +322,17 @@ func (s *Stmt) Exec() error {
 //
 // https://www.sqlite.org/c3ref/bind_parameter_count.html
 func (s *Stmt) BindCount() int {
+	defer s.c.unlock(s.c.lock())
+	if s.bindCount >= 0 {
+		return s.bindCount
+	}
 	r, err := s.c.api.bindCount.Call(s.c.ctx,
 		uint64(s.handle))
 	if err != nil {
 		panic(err)
 	}
-	return int(r[0])
+	s.bindCount = int(r[0])
+	return s.bindCount
 }
 
 // BindIndex returns the index of a parameter in the prepared statement
@@ -115,6 +340,7 @@ func (s *Stmt) BindCount() int {
 //
 // https://www.sqlite.org/c3ref/bind_parameter_index.html
 func (s *Stmt) BindIndex(name string) int {
+	defer s.c.unlock(s.c.lock())
 	defer s.c.arena.reset()
 	namePtr := s.c.arena.string(name)
 	r, err := s.c.api.bindIndex.Call(s.c.ctx,
@@ -130,6 +356,7 @@ func (s *Stmt) BindIndex(name string) int {
 //
 // https://www.sqlite.org/c3ref/bind_parameter_name.html
 func (s *Stmt) BindName(param int) string {
+	defer s.c.unlock(s.c.lock())
 	r, err := s.c.api.bindName.Call(s.c.ctx,
 		uint64(s.handle), uint64(param))
 	if err != nil {
@@ -143,6 +370,19 @@ func (s *Stmt) BindName(param int) string {
 	return s.c.mem.readString(ptr, _MAX_STRING)
 }
 
+// BindNames returns the names of all parameters in the prepared statement.
+// The name of the parameter with index i is at names[i-1].
+// Nameless parameters have an empty name.
+//
+// https://www.sqlite.org/c3ref/bind_parameter_name.html
+func (s *Stmt) BindNames() (names []string) {
+	names = make([]string, s.BindCount())
+	for i := range names {
+		names[i] = s.BindName(i + 1)
+	}
+	return names
+}
+
 // BindBool binds a bool to the prepared statement.
 // The leftmost SQL parameter has an index of 1.
 // SQLite does not have a separate boolean storage class.
@@ -169,6 +409,10 @@ func (s *Stmt) BindInt(param int, value int) error {
 //
 // https://www.sqlite.org/c3ref/bind_blob.html
 func (s *Stmt) BindInt64(param int, value int64) error {
+	defer s.c.unlock(s.c.lock())
+	if err := s.checkBind(); err != nil {
+		return err
+	}
 	r, err := s.c.api.bindInteger.Call(s.c.ctx,
 		uint64(s.handle), uint64(param), uint64(value))
 	if err != nil {
@@ -177,11 +421,28 @@ func (s *Stmt) BindInt64(param int, value int64) error {
 	return s.c.error(r[0])
 }
 
+// BindUint64 binds a uint64 to the prepared statement.
+// The leftmost SQL parameter has an index of 1.
+//
+// SQLite integers are signed, so the bits of value are reinterpreted as an int64:
+// values greater than [math.MaxInt64] are stored as negative integers,
+// and sort and compare as such in SQL.
+// Use [Stmt.ColumnUint64] to read them back.
+//
+// https://www.sqlite.org/c3ref/bind_blob.html
+func (s *Stmt) BindUint64(param int, value uint64) error {
+	return s.BindInt64(param, int64(value))
+}
+
 // BindFloat binds a float64 to the prepared statement.
 // The leftmost SQL parameter has an index of 1.
 //
 // https://www.sqlite.org/c3ref/bind_blob.html
 func (s *Stmt) BindFloat(param int, value float64) error {
+	defer s.c.unlock(s.c.lock())
+	if err := s.checkBind(); err != nil {
+		return err
+	}
 	r, err := s.c.api.bindFloat.Call(s.c.ctx,
 		uint64(s.handle), uint64(param), math.Float64bits(value))
 	if err != nil {
@@ -192,9 +453,15 @@ func (s *Stmt) BindFloat(param int, value float64) error {
 
 // BindText binds a string to the prepared statement.
 // The leftmost SQL parameter has an index of 1.
+// Text is always passed to SQLite as UTF-8;
+// SQLite converts it to the database text encoding as needed.
 //
 // https://www.sqlite.org/c3ref/bind_blob.html
 func (s *Stmt) BindText(param int, value string) error {
+	defer s.c.unlock(s.c.lock())
+	if err := s.checkBind(); err != nil {
+		return err
+	}
 	ptr := s.c.newString(value)
 	r, err := s.c.api.bindText.Call(s.c.ctx,
 		uint64(s.handle), uint64(param),
@@ -206,12 +473,26 @@ func (s *Stmt) BindText(param int, value string) error {
 	return s.c.error(r[0])
 }
 
+// BindText16 binds UTF-16 text to the prepared statement.
+// The leftmost SQL parameter has an index of 1.
+// The text is converted to UTF-8 before being passed to SQLite,
+// see [Stmt.BindText].
+//
+// https://www.sqlite.org/c3ref/bind_blob.html
+func (s *Stmt) BindText16(param int, value []uint16) error {
+	return s.BindText(param, string(utf16.Decode(value)))
+}
+
 // BindBlob binds a []byte to the prepared statement.
 // The leftmost SQL parameter has an index of 1.
 // Binding a nil slice is the same as calling [Stmt.BindNull].
 //
 // https://www.sqlite.org/c3ref/bind_blob.html
 func (s *Stmt) BindBlob(param int, value []byte) error {
+	defer s.c.unlock(s.c.lock())
+	if err := s.checkBind(); err != nil {
+		return err
+	}
 	ptr := s.c.newBytes(value)
 	r, err := s.c.api.bindBlob.Call(s.c.ctx,
 		uint64(s.handle), uint64(param),
@@ -228,6 +509,10 @@ func (s *Stmt) BindBlob(param int, value []byte) error {
 //
 // https://www.sqlite.org/c3ref/bind_blob.html
 func (s *Stmt) BindZeroBlob(param int, n int64) error {
+	defer s.c.unlock(s.c.lock())
+	if err := s.checkBind(); err != nil {
+		return err
+	}
 	r, err := s.c.api.bindZeroBlob.Call(s.c.ctx,
 		uint64(s.handle), uint64(param), uint64(n))
 	if err != nil {
@@ -241,6 +526,10 @@ func (s *Stmt) BindZeroBlob(param int, n int64) error {
 //
 // https://www.sqlite.org/c3ref/bind_blob.html
 func (s *Stmt) BindNull(param int) error {
+	defer s.c.unlock(s.c.lock())
+	if err := s.checkBind(); err != nil {
+		return err
+	}
 	r, err := s.c.api.bindNull.Call(s.c.ctx,
 		uint64(s.handle), uint64(param))
 	if err != nil {
@@ -256,14 +545,152 @@ func (s *Stmt) BindNull(param int) error {
 func (s *Stmt) BindTime(param int, value time.Time, format TimeFormat) error {
 	switch v := format.Encode(value).(type) {
 	case string:
-		s.BindText(param, v)
+		return s.BindText(param, v)
 	case int64:
-		s.BindInt64(param, v)
+		return s.BindInt64(param, v)
 	case float64:
-		s.BindFloat(param, v)
+		return s.BindFloat(param, v)
 	default:
 		panic(assertErr())
 	}
+}
+
+// BindTimeAuto binds a [time.Time] to the prepared statement,
+// using the connection's default format, see [Conn.SetTimeFormat].
+// The leftmost SQL parameter has an index of 1.
+//
+// https://www.sqlite.org/c3ref/bind_blob.html
+func (s *Stmt) BindTimeAuto(param int, value time.Time) error {
+	return s.BindTime(param, value, s.c.timeFormat)
+}
+
+// BindDuration binds a [time.Duration] to the prepared statement,
+// as an integer number of units (e.g. [time.Millisecond]),
+// truncated towards zero.
+// If unit is not positive, nanoseconds are used.
+// The leftmost SQL parameter has an index of 1.
+//
+// https://www.sqlite.org/c3ref/bind_blob.html
+func (s *Stmt) BindDuration(param int, value time.Duration, unit time.Duration) error {
+	if unit <= 0 {
+		unit = time.Nanosecond
+	}
+	return s.BindInt64(param, int64(value/unit))
+}
+
+// BindAddr binds a [netip.Addr] to the prepared statement,
+// as text in its canonical form (see [netip.Addr.String]).
+// The zero Addr is bound as NULL.
+// The leftmost SQL parameter has an index of 1.
+//
+// https://www.sqlite.org/c3ref/bind_blob.html
+func (s *Stmt) BindAddr(param int, value netip.Addr) error {
+	if !value.IsValid() {
+		return s.BindNull(param)
+	}
+	return s.BindText(param, value.String())
+}
+
+// BindAddrBlob binds a [netip.Addr] to the prepared statement,
+// as a 16 byte BLOB (see [netip.Addr.As16]).
+// IPv4 addresses are bound as IPv4-mapped IPv6 addresses,
+// and zones are dropped.
+// The zero Addr is bound as NULL.
+// The leftmost SQL parameter has an index of 1.
+//
+// https://www.sqlite.org/c3ref/bind_blob.html
+func (s *Stmt) BindAddrBlob(param int, value netip.Addr) error {
+	if !value.IsValid() {
+		return s.BindNull(param)
+	}
+	b := value.As16()
+	return s.BindBlob(param, b[:])
+}
+
+// BindValue binds a Go value to the prepared statement,
+// using the Bind method for its type.
+// The leftmost SQL parameter has an index of 1.
+//
+// Supported types are nil, bool, int, int64, float64, string, []byte,
+// [ZeroBlob], [time.Time] (bound with [Stmt.BindTimeAuto]),
+// [time.Duration] (bound as nanoseconds),
+// and [netip.Addr] and [net.IP] (bound as text, see [Stmt.BindAddr]).
+// Other types that implement [encoding.TextMarshaler],
+// or else [fmt.Stringer], are bound as text.
+//
+// https://www.sqlite.org/c3ref/bind_blob.html
+func (s *Stmt) BindValue(param int, value any) error {
+	switch v := value.(type) {
+	case nil:
+		return s.BindNull(param)
+	case bool:
+		return s.BindBool(param, v)
+	case int:
+		return s.BindInt(param, v)
+	case int64:
+		return s.BindInt64(param, v)
+	case float64:
+		return s.BindFloat(param, v)
+	case string:
+		return s.BindText(param, v)
+	case []byte:
+		return s.BindBlob(param, v)
+	case ZeroBlob:
+		return s.BindZeroBlob(param, int64(v))
+	case time.Time:
+		return s.BindTimeAuto(param, v)
+	case time.Duration:
+		return s.BindDuration(param, v, time.Nanosecond)
+	case netip.Addr:
+		return s.BindAddr(param, v)
+	case net.IP:
+		if v == nil {
+			return s.BindNull(param)
+		}
+		return s.BindText(param, v.String())
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return err
+		}
+		return s.BindText(param, string(text))
+	case fmt.Stringer:
+		return s.BindText(param, v.String())
+	default:
+		return fmt.Errorf("sqlite3: unsupported parameter type %T", value)
+	}
+}
+
+// BindMap binds the values in m to the named parameters of the prepared statement,
+// using [Stmt.BindValue].
+// Keys are matched to parameters :key, @key or $key,
+// or used as is if they start with one of those prefixes.
+// Unknown keys, and keys that match more than one parameter, are an error.
+// Parameters without a key in m are left unchanged (NULL, unless previously bound).
+//
+// https://www.sqlite.org/c3ref/bind_parameter_index.html
+func (s *Stmt) BindMap(m map[string]any) error {
+	for key, value := range m {
+		param := 0
+		if key != "" && strings.ContainsRune(":@$", rune(key[0])) {
+			param = s.BindIndex(key)
+		} else {
+			for _, prefix := range [...]string{":", "@", "$"} {
+				if i := s.BindIndex(prefix + key); i != 0 {
+					if param != 0 {
+						return fmt.Errorf("sqlite3: ambiguous named parameter: %s", key)
+					}
+					param = i
+				}
+			}
+		}
+		if param == 0 {
+			return fmt.Errorf("sqlite3: unknown named parameter: %s", key)
+		}
+		if err := s.BindValue(param, value); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -271,19 +698,40 @@ func (s *Stmt) BindTime(param int, value time.Time, format TimeFormat) error {
 //
 // https://www.sqlite.org/c3ref/column_count.html
 func (s *Stmt) ColumnCount() int {
+	defer s.c.unlock(s.c.lock())
+	if s.colCount >= 0 {
+		return s.colCount
+	}
 	r, err := s.c.api.columnCount.Call(s.c.ctx,
 		uint64(s.handle))
 	if err != nil {
 		panic(err)
 	}
-	return int(r[0])
+	s.colCount = int(r[0])
+	return s.colCount
+}
+
+// DataCount returns the number of columns in the current row of the result set.
+// Unlike [Stmt.ColumnCount], which is known after the statement is prepared,
+// DataCount returns 0 unless the last call to [Stmt.Step] returned a row.
+//
+// https://www.sqlite.org/c3ref/data_count.html
+func (s *Stmt) DataCount() int {
+	if !s.row {
+		return 0
+	}
+	return s.ColumnCount()
 }
 
 // ColumnName returns the name of the result column.
 // The leftmost column of the result set has the index 0.
 //
+// Names need not be unique: "SELECT a.id, b.id" has two columns named "id".
+// Use AS clauses to give columns distinct names.
+//
 // https://www.sqlite.org/c3ref/column_name.html
 func (s *Stmt) ColumnName(col int) string {
+	defer s.c.unlock(s.c.lock())
 	r, err := s.c.api.columnName.Call(s.c.ctx,
 		uint64(s.handle), uint64(col))
 	if err != nil {
@@ -297,11 +745,26 @@ func (s *Stmt) ColumnName(col int) string {
 	return s.c.mem.readString(ptr, _MAX_STRING)
 }
 
+// ColumnIndex returns the index of the first result column named name,
+// and true, or -1 and false if there is no such column.
+// Names are compared exactly, see [Stmt.ColumnName].
+//
+// https://www.sqlite.org/c3ref/column_name.html
+func (s *Stmt) ColumnIndex(name string) (int, bool) {
+	for i, n := 0, s.ColumnCount(); i < n; i++ {
+		if s.ColumnName(i) == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
 // ColumnType returns the initial [Datatype] of the result column.
 // The leftmost column of the result set has the index 0.
 //
 // https://www.sqlite.org/c3ref/column_blob.html
 func (s *Stmt) ColumnType(col int) Datatype {
+	defer s.c.unlock(s.c.lock())
 	r, err := s.c.api.columnType.Call(s.c.ctx,
 		uint64(s.handle), uint64(col))
 	if err != nil {
@@ -310,6 +773,15 @@ func (s *Stmt) ColumnType(col int) Datatype {
 	return Datatype(r[0])
 }
 
+// ColumnIsNull reports whether the result column is NULL.
+// Empty strings and zero values are not NULL.
+// The leftmost column of the result set has the index 0.
+//
+// https://www.sqlite.org/c3ref/column_blob.html
+func (s *Stmt) ColumnIsNull(col int) bool {
+	return s.ColumnType(col) == NULL
+}
+
 // ColumnBool returns the value of the result column as a bool.
 // The leftmost column of the result set has the index 0.
 // SQLite does not have a separate boolean storage class.
@@ -337,6 +809,7 @@ func (s *Stmt) ColumnInt(col int) int {
 //
 // https://www.sqlite.org/c3ref/column_blob.html
 func (s *Stmt) ColumnInt64(col int) int64 {
+	defer s.c.unlock(s.c.lock())
 	r, err := s.c.api.columnInteger.Call(s.c.ctx,
 		uint64(s.handle), uint64(col))
 	if err != nil {
@@ -345,11 +818,24 @@ func (s *Stmt) ColumnInt64(col int) int64 {
 	return int64(r[0])
 }
 
+// ColumnUint64 returns the value of the result column as a uint64.
+// The leftmost column of the result set has the index 0.
+//
+// The bits of the int64 stored by SQLite are reinterpreted as a uint64,
+// which reverses [Stmt.BindUint64]:
+// negative integers are returned as values greater than [math.MaxInt64].
+//
+// https://www.sqlite.org/c3ref/column_blob.html
+func (s *Stmt) ColumnUint64(col int) uint64 {
+	return uint64(s.ColumnInt64(col))
+}
+
 // ColumnFloat returns the value of the result column as a float64.
 // The leftmost column of the result set has the index 0.
 //
 // https://www.sqlite.org/c3ref/column_blob.html
 func (s *Stmt) ColumnFloat(col int) float64 {
+	defer s.c.unlock(s.c.lock())
 	r, err := s.c.api.columnFloat.Call(s.c.ctx,
 		uint64(s.handle), uint64(col))
 	if err != nil {
@@ -383,11 +869,86 @@ func (s *Stmt) ColumnTime(col int, format TimeFormat) time.Time {
 	return t
 }
 
+// ColumnTimeAuto returns the value of the result column as a [time.Time],
+// using the connection's default format, see [Conn.SetTimeFormat].
+// The leftmost column of the result set has the index 0.
+//
+// https://www.sqlite.org/c3ref/column_blob.html
+func (s *Stmt) ColumnTimeAuto(col int) time.Time {
+	return s.ColumnTime(col, s.c.decodeTimeFormat())
+}
+
+// ColumnDuration returns the value of the result column as a [time.Duration],
+// interpreting it as an integer number of units (e.g. [time.Millisecond]).
+// If unit is not positive, nanoseconds are used.
+// Values that don't fit a time.Duration overflow.
+// The leftmost column of the result set has the index 0.
+//
+// https://www.sqlite.org/c3ref/column_blob.html
+func (s *Stmt) ColumnDuration(col int, unit time.Duration) time.Duration {
+	if unit <= 0 {
+		unit = time.Nanosecond
+	}
+	return time.Duration(s.ColumnInt64(col)) * unit
+}
+
+// ColumnAddr returns the value of the result column as a [netip.Addr].
+// TEXT is parsed with [netip.ParseAddr],
+// and a 4 or 16 byte BLOB is converted with [netip.AddrFromSlice]
+// (use [netip.Addr.Unmap] to get IPv4 addresses stored with [Stmt.BindAddrBlob]).
+// NULL is the zero Addr.
+// The leftmost column of the result set has the index 0.
+//
+// https://www.sqlite.org/c3ref/column_blob.html
+func (s *Stmt) ColumnAddr(col int) netip.Addr {
+	addr, err := s.columnAddr(col)
+	if err != nil {
+		s.err = err
+	}
+	return addr
+}
+
+func (s *Stmt) columnAddr(col int) (addr netip.Addr, err error) {
+	switch s.ColumnType(col) {
+	case TEXT:
+		addr, err = netip.ParseAddr(s.ColumnText(col))
+	case BLOB:
+		b := s.ColumnRawBlob(col)
+		var ok bool
+		if addr, ok = netip.AddrFromSlice(b); !ok {
+			err = fmt.Errorf("sqlite3: invalid address: %d byte BLOB", len(b))
+		}
+	case NULL:
+	default:
+		err = fmt.Errorf("sqlite3: invalid address: %v", s.ColumnType(col))
+	}
+	return addr, err
+}
+
 // ColumnText returns the value of the result column as a string.
 // The leftmost column of the result set has the index 0.
+// Text is always returned as UTF-8,
+// regardless of the database text encoding.
 //
 // https://www.sqlite.org/c3ref/column_blob.html
 func (s *Stmt) ColumnText(col int) string {
+	return string(s.columnRawText(col))
+}
+
+// ColumnTextBytes appends to buf and returns
+// the value of the result column as UTF-8 text,
+// without allocating a string, see [Stmt.ColumnText].
+// The leftmost column of the result set has the index 0.
+//
+// https://www.sqlite.org/c3ref/column_blob.html
+func (s *Stmt) ColumnTextBytes(col int, buf []byte) []byte {
+	return append(buf[0:0], s.columnRawText(col)...)
+}
+
+// columnRawText returns a view of the column text in SQLite's memory,
+// valid until the next call to a column accessor or to Step.
+func (s *Stmt) columnRawText(col int) []byte {
+	defer s.c.unlock(s.c.lock())
 	r, err := s.c.api.columnText.Call(s.c.ctx,
 		uint64(s.handle), uint64(col))
 	if err != nil {
@@ -396,12 +957,15 @@ func (s *Stmt) ColumnText(col int) string {
 
 	ptr := uint32(r[0])
 	if ptr == 0 {
-		r, err = s.c.api.errcode.Call(s.c.ctx, uint64(s.handle))
+		r, err = s.c.api.errcode.Call(s.c.ctx, uint64(s.c.handle))
 		if err != nil {
 			panic(err)
 		}
-		s.err = s.c.error(r[0])
-		return ""
+		// NULL, or an empty value, is not an error.
+		if r[0] != _ROW && r[0] != _DONE {
+			s.err = s.c.error(r[0])
+		}
+		return nil
 	}
 
 	r, err = s.c.api.columnBytes.Call(s.c.ctx,
@@ -410,8 +974,16 @@ func (s *Stmt) ColumnText(col int) string {
 		panic(err)
 	}
 
-	mem := s.c.mem.view(ptr, uint32(r[0]))
-	return string(mem)
+	return s.c.mem.view(ptr, uint32(r[0]))
+}
+
+// ColumnText16 returns the value of the result column as UTF-16 text.
+// The leftmost column of the result set has the index 0.
+// The text is converted from UTF-8, see [Stmt.ColumnText].
+//
+// https://www.sqlite.org/c3ref/column_blob.html
+func (s *Stmt) ColumnText16(col int) []uint16 {
+	return utf16.Encode([]rune(s.ColumnText(col)))
 }
 
 // ColumnBlob appends to buf and returns
@@ -420,6 +992,21 @@ func (s *Stmt) ColumnText(col int) string {
 //
 // https://www.sqlite.org/c3ref/column_blob.html
 func (s *Stmt) ColumnBlob(col int, buf []byte) []byte {
+	return append(buf[0:0], s.ColumnRawBlob(col)...)
+}
+
+// ColumnRawBlob returns the value of the result column as a []byte,
+// without copying it from SQLite's memory.
+// The leftmost column of the result set has the index 0.
+//
+// The []byte aliases memory owned by SQLite: it must not be modified,
+// and it's only valid until the next call to [Stmt.Step], [Stmt.Reset],
+// [Stmt.Close], or to another column accessor for the same column.
+// Callers must copy the []byte to retain it longer, see [Stmt.ColumnBlob].
+//
+// https://www.sqlite.org/c3ref/column_blob.html
+func (s *Stmt) ColumnRawBlob(col int) []byte {
+	defer s.c.unlock(s.c.lock())
 	r, err := s.c.api.columnBlob.Call(s.c.ctx,
 		uint64(s.handle), uint64(col))
 	if err != nil {
@@ -428,12 +1015,15 @@ func (s *Stmt) ColumnBlob(col int, buf []byte) []byte {
 
 	ptr := uint32(r[0])
 	if ptr == 0 {
-		r, err = s.c.api.errcode.Call(s.c.ctx, uint64(s.handle))
+		r, err = s.c.api.errcode.Call(s.c.ctx, uint64(s.c.handle))
 		if err != nil {
 			panic(err)
 		}
-		s.err = s.c.error(r[0])
-		return buf[0:0]
+		// NULL, or an empty value, is not an error.
+		if r[0] != _ROW && r[0] != _DONE {
+			s.err = s.c.error(r[0])
+		}
+		return nil
 	}
 
 	r, err = s.c.api.columnBytes.Call(s.c.ctx,
@@ -442,21 +1032,204 @@ func (s *Stmt) ColumnBlob(col int, buf []byte) []byte {
 		panic(err)
 	}
 
-	mem := s.c.mem.view(ptr, uint32(r[0]))
-	return append(buf[0:0], mem...)
+	return s.c.mem.view(ptr, uint32(r[0]))
 }
 
-// Return true if stmt is an empty SQL statement.
-// This is used as an optimization.
-// It's OK to always return false here.
-func emptyStatement(stmt string) bool {
-	for _, b := range []byte(stmt) {
-		switch b {
-		case ' ', '\n', '\r', '\t', '\v', '\f':
-		case ';':
-		default:
-			return false
+// Scan copies the columns in the current row into the values pointed at by dest.
+// The number of values in dest must be the same as the number of columns.
+//
+// Scan supports pointers to int, int64, float64, bool, string, []byte,
+// [time.Time] and [database/sql.NullTime] (decoded as in [Stmt.ColumnTimeAuto]),
+// [time.Duration] (as nanoseconds), [netip.Addr] (as in [Stmt.ColumnAddr]),
+// and any (set to an int64, float64, string, []byte or nil).
+// NULL is scanned into these as the zero value.
+// Other destinations must implement [database/sql.Scanner],
+// like [database/sql.NullString], and are passed the same value as an any.
+// An empty BLOB is scanned as an empty, not nil, []byte.
+//
+// https://www.sqlite.org/c3ref/column_blob.html
+func (s *Stmt) Scan(dest ...any) error {
+	if n := s.ColumnCount(); len(dest) != n {
+		return fmt.Errorf("sqlite3: scan: got %d destinations, want %d", len(dest), n)
+	}
+	for i, d := range dest {
+		if err := s.scanColumn(i, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ScanColumn steps the statement to completion,
+// appending the value of the result column in each row to dest,
+// then resets the statement.
+// The leftmost column of the result set has the index 0.
+//
+// ScanColumn supports pointers to []int64 (INTEGER values),
+// []float64 (FLOAT or INTEGER values), []string (TEXT values)
+// and [][]byte (BLOB values).
+// Values of other datatypes, including NULL, are an error.
+//
+// https://www.sqlite.org/c3ref/column_blob.html
+func (s *Stmt) ScanColumn(col int, dest any) error {
+	var want string
+	switch dest.(type) {
+	case *[]int64:
+		want = "INTEGER"
+	case *[]float64:
+		want = "FLOAT"
+	case *[]string:
+		want = "TEXT"
+	case *[][]byte:
+		want = "BLOB"
+	default:
+		return fmt.Errorf("sqlite3: scan column %d: unsupported destination %T", col, dest)
+	}
+
+	for row := 0; s.Step(); row++ {
+		typ := s.ColumnType(col)
+		switch d := dest.(type) {
+		case *[]int64:
+			if typ == INTEGER {
+				*d = append(*d, s.ColumnInt64(col))
+				continue
+			}
+		case *[]float64:
+			if typ == FLOAT || typ == INTEGER {
+				*d = append(*d, s.ColumnFloat(col))
+				continue
+			}
+		case *[]string:
+			if typ == TEXT {
+				*d = append(*d, s.ColumnText(col))
+				continue
+			}
+		case *[][]byte:
+			if typ == BLOB {
+				*d = append(*d, s.ColumnBlob(col, nil))
+				continue
+			}
+		}
+		s.Reset()
+		return fmt.Errorf("sqlite3: scan column %d: row %d is %v, want %s", col, row, typ, want)
+	}
+	return s.Reset()
+}
+
+func (s *Stmt) scanColumn(col int, dest any) error {
+	switch d := dest.(type) {
+	case *int:
+		*d = s.ColumnInt(col)
+	case *int64:
+		*d = s.ColumnInt64(col)
+	case *float64:
+		*d = s.ColumnFloat(col)
+	case *bool:
+		*d = s.ColumnBool(col)
+	case *string:
+		*d = s.ColumnText(col)
+	case *[]byte:
+		if s.ColumnType(col) == NULL {
+			*d = nil
+		} else {
+			// An empty BLOB is not NULL.
+			*d = s.ColumnBlob(col, []byte{})
+		}
+	case *time.Time:
+		t, _, err := s.columnTime(col)
+		if err != nil {
+			return err
+		}
+		*d = t
+	case *sql.NullTime:
+		t, ok, err := s.columnTime(col)
+		if err != nil {
+			return err
+		}
+		d.Time, d.Valid = t, ok
+	case *time.Duration:
+		*d = s.ColumnDuration(col, time.Nanosecond)
+	case *netip.Addr:
+		addr, err := s.columnAddr(col)
+		if err != nil {
+			return fmt.Errorf("sqlite3: scan: column %d: %w", col, err)
+		}
+		*d = addr
+	case *any:
+		*d = s.columnValue(col)
+	case sql.Scanner:
+		if err := d.Scan(s.columnValue(col)); err != nil {
+			return fmt.Errorf("sqlite3: scan: column %d: %w", col, err)
+		}
+	default:
+		return fmt.Errorf("sqlite3: scan: column %d: unsupported destination %T", col, dest)
+	}
+	return nil
+}
+
+// columnTime decodes the value of the result column as in [Stmt.ColumnTimeAuto],
+// and reports if it's not NULL.
+func (s *Stmt) columnTime(col int) (time.Time, bool, error) {
+	v := s.columnValue(col)
+	if v == nil {
+		return time.Time{}, false, nil
+	}
+	t, err := s.c.decodeTimeFormat().Decode(v)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("sqlite3: scan: column %d: %w", col, err)
+	}
+	return t, true, nil
+}
+
+// columnValue returns the value of the result column
+// as an int64, float64, string, []byte or nil.
+// An empty BLOB is an empty, not nil, []byte.
+func (s *Stmt) columnValue(col int) any {
+	switch s.ColumnType(col) {
+	case INTEGER:
+		return s.ColumnInt64(col)
+	case FLOAT:
+		return s.ColumnFloat(col)
+	case TEXT:
+		return s.ColumnText(col)
+	case BLOB:
+		return s.ColumnBlob(col, []byte{})
+	case NULL:
+		return nil
+	default:
+		panic(assertErr())
+	}
+}
+
+// Return the explain mode of the first statement in sql:
+// 0 for a normal statement, 1 for EXPLAIN, 2 for EXPLAIN QUERY PLAN.
+func explainMode(sql string) int {
+	sql, ok := keyword(sql, "EXPLAIN")
+	if !ok {
+		return 0
+	}
+	if sql, ok = keyword(sql, "QUERY"); ok {
+		if _, ok = keyword(sql, "PLAN"); ok {
+			return 2
+		}
+	}
+	return 1
+}
+
+// Skip whitespace and comments, then match kw case insensitively,
+// returning the remainder of sql.
+func keyword(sql, kw string) (string, bool) {
+	sql = util.SkipSpace(sql)
+	if len(sql) < len(kw) || !strings.EqualFold(sql[:len(kw)], kw) {
+		return "", false
+	}
+	sql = sql[len(kw):]
+	if len(sql) > 0 {
+		switch b := sql[0]; {
+		case b == '_' || b == '$' || b >= 0x80 ||
+			'0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z':
+			return "", false
 		}
 	}
-	return true
+	return sql, true
 }
@@ -2,36 +2,17 @@ package sqlite3
 
 import (
 	"testing"
-)
-
-func Test_emptyStatement(t *testing.T) {
-	t.Parallel()
 
-	tests := []struct {
-		name string
-		stmt string
-		want bool
-	}{
-		{"empty", "", true},
-		{"space", " ", true},
-		{"separator", ";\n ", true},
-		{"begin", "BEGIN", false},
-		{"select", "SELECT 1;", false},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := emptyStatement(tt.stmt); got != tt.want {
-				t.Errorf("got %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
+	"github.com/ncruces/go-sqlite3/internal/util"
+)
 
 func Fuzz_emptyStatement(f *testing.F) {
 	f.Add("")
 	f.Add(" ")
 	f.Add(";\n ")
 	f.Add("; ;\v")
+	f.Add("-- comment")
+	f.Add("/* comment */;")
 	f.Add("BEGIN")
 	f.Add("SELECT 1;")
 
@@ -43,7 +24,7 @@ func Fuzz_emptyStatement(f *testing.F) {
 
 	f.Fuzz(func(t *testing.T, sql string) {
 		// If empty, SQLite parses it as empty.
-		if emptyStatement(sql) {
+		if util.EmptyStatement(sql) {
 			stmt, tail, err := db.Prepare(sql)
 			if err != nil {
 				t.Errorf("%q, %v", sql, err)
@@ -58,3 +39,27 @@ func Fuzz_emptyStatement(f *testing.F) {
 		}
 	})
 }
+
+func Test_explainMode(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want int
+	}{
+		{"SELECT 1", 0},
+		{"EXPLAIN SELECT 1", 1},
+		{"explain\nselect 1", 1},
+		{"  -- comment\n /* block */ EXPLAIN SELECT 1", 1},
+		{"EXPLAIN QUERY PLAN SELECT 1", 2},
+		{"explain /* x */ query\tplan SELECT 1", 2},
+		{"EXPLAIN QUERY_PLAN", 1},
+		{"EXPLAINED", 0},
+		{"-- EXPLAIN", 0},
+		{"/* EXPLAIN", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := explainMode(tt.sql); got != tt.want {
+			t.Errorf("explainMode(%q) = %d, want %d", tt.sql, got, tt.want)
+		}
+	}
+}
@@ -3,8 +3,16 @@ package tests
 import (
 	"context"
 	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/ncruces/go-sqlite3"
 	_ "github.com/ncruces/go-sqlite3/embed"
@@ -225,3 +233,922 @@ func TestConn_Prepare_invalid(t *testing.T) {
 		t.Error("got message: ", got)
 	}
 }
+
+func TestConn_ExecScript(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	n, err := db.ExecScript(`
+		CREATE TABLE test (col UNIQUE);
+		-- comment
+		;;
+		INSERT INTO test VALUES (1);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("got %d, want 2", n)
+	}
+
+	n, err = db.ExecScript(`
+		INSERT INTO test VALUES (2);
+		INSERT INTO test VALUES (1);
+		INSERT INTO test VALUES (3);
+	`)
+	if n != 1 {
+		t.Errorf("got %d, want 1", n)
+	}
+	var serr *sqlite3.Error
+	if !errors.As(err, &serr) {
+		t.Fatalf("got %T, want sqlite3.Error", err)
+	}
+	if rc := serr.Code(); rc != sqlite3.CONSTRAINT {
+		t.Errorf("got %d, want sqlite3.CONSTRAINT", rc)
+	}
+	if got := strings.TrimSpace(serr.SQL()); got != `INSERT INTO test VALUES (1);` {
+		t.Error("got SQL: ", got)
+	}
+
+	n, err = db.ExecScript(`SELECT 1; SELEC 2;`)
+	if n != 1 {
+		t.Errorf("got %d, want 1", n)
+	}
+	if !errors.As(err, &serr) {
+		t.Fatalf("got %T, want sqlite3.Error", err)
+	}
+	if got := serr.SQL(); got != `SELEC 2;` {
+		t.Error("got SQL: ", got)
+	}
+}
+
+func TestConn_ExecScript_offset(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	script := "CREATE TABLE test (col UNIQUE);\n" +
+		"INSERT INTO test VALUES (1);\n" +
+		"  INSERT INTO test VALUES (1);\n" +
+		"INSERT INTO test VALUES (2);\n"
+
+	n, err := db.ExecScript(script)
+	if n != 2 {
+		t.Errorf("got %d, want 2", n)
+	}
+	var serr *sqlite3.Error
+	if !errors.As(err, &serr) {
+		t.Fatalf("got %T, want sqlite3.Error", err)
+	}
+	if got, want := serr.Offset(), strings.Index(script, "  INSERT")+2; got != want {
+		t.Errorf("got offset %d, want %d", got, want)
+	}
+	if line := 1 + strings.Count(script[:serr.Offset()], "\n"); line != 3 {
+		t.Errorf("got line %d, want 3", line)
+	}
+
+	_, err = db.ExecScript("SELECT 1;\nSELECT 2;\nSELEC 3;")
+	if !errors.As(err, &serr) {
+		t.Fatalf("got %T, want sqlite3.Error", err)
+	}
+	if got := serr.Offset(); got != 20 {
+		t.Errorf("got offset %d, want 20", got)
+	}
+}
+
+func TestConn_TempStore(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.TempStore(sqlite3.TEMP_STORE_MEMORY)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`PRAGMA temp_store`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := sqlite3.TempStore(stmt.ColumnInt(0)); got != sqlite3.TEMP_STORE_MEMORY {
+		t.Errorf("got %d, want sqlite3.TEMP_STORE_MEMORY", got)
+	}
+}
+
+func TestConn_WindowedChanges(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE test (col);
+		INSERT INTO test VALUES (1), (2), (3);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := db.TotalChanges(); err != nil {
+		t.Fatal(err)
+	} else if got != 3 {
+		t.Errorf("got %d, want 3", got)
+	}
+
+	err = db.ResetChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Exec(`UPDATE test SET col = col + 1 WHERE col > 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := db.WindowedChanges(); got != 2 {
+		t.Errorf("got %d, want 2", got)
+	}
+	if got, err := db.TotalChanges(); err != nil {
+		t.Fatal(err)
+	} else if got != 5 {
+		t.Errorf("got %d, want 5", got)
+	}
+}
+
+func TestConn_OpenFS(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "test.db")
+	db, err := sqlite3.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Exec(`
+		CREATE TABLE test (col);
+		INSERT INTO test VALUES ('embedded');
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{"data/test.db": &fstest.MapFile{Data: data}}
+
+	db, err = sqlite3.OpenFS(fsys, "data/test.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, _, err := db.Prepare(`SELECT col FROM test`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnText(0); got != "embedded" {
+		t.Errorf("got %q, want embedded", got)
+	}
+	err = stmt.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Exec(`INSERT INTO test VALUES ('written')`)
+	if err == nil {
+		t.Fatal("want error")
+	}
+	var serr *sqlite3.Error
+	if !errors.As(err, &serr) {
+		t.Fatalf("got %T, want sqlite3.Error", err)
+	}
+	if rc := serr.Code(); rc != sqlite3.READONLY {
+		t.Errorf("got %d, want sqlite3.READONLY", rc)
+	}
+
+	_, err = sqlite3.OpenFS(fsys, "missing.db")
+	if err == nil {
+		t.Error("want error")
+	}
+
+	// Files that only implement io.Seeker are also supported.
+	db, err = sqlite3.OpenFS(seekerFS{fsys}, "data/test.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Query(`SELECT col FROM test`, nil, func(stmt *sqlite3.Stmt) error {
+		if got := stmt.ColumnText(0); got != "embedded" {
+			t.Errorf("got %q, want embedded", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// seekerFS hides the io.ReaderAt implementation of its files.
+type seekerFS struct{ fs.FS }
+
+func (f seekerFS) Open(name string) (fs.File, error) {
+	file, err := f.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return seekerFile{file.(seekerFileInterface)}, nil
+}
+
+type seekerFileInterface interface {
+	fs.File
+	io.Seeker
+}
+
+type seekerFile struct{ seekerFileInterface }
+
+type constReader byte
+
+func (r constReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(r)
+	}
+	return len(p), nil
+}
+
+func TestConn_RandSource(t *testing.T) {
+	// Not parallel: sets a global.
+	defer func(old io.Reader) { sqlite3.RandSource = old }(sqlite3.RandSource)
+	sqlite3.RandSource = constReader(42)
+
+	random := func() (res [2]int64) {
+		db, err := sqlite3.Open(":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+
+		stmt, _, err := db.Prepare(`SELECT random() UNION ALL SELECT random()`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+
+		for i := range res {
+			if !stmt.Step() {
+				t.Fatal(stmt.Err())
+			}
+			res[i] = stmt.ColumnInt64(0)
+		}
+		return res
+	}
+
+	a, b := random(), random()
+	if a != b {
+		t.Errorf("got %v and %v, want equal", a, b)
+	}
+	if a[0] == a[1] {
+		t.Errorf("got %v, want different values", a)
+	}
+}
+
+func TestConn_SetSerialized(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetSerialized(true)
+
+	err = db.Exec(`CREATE TABLE test (col)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				stmt, _, err := db.Prepare(`INSERT INTO test VALUES (?)`)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				stmt.BindText(1, "value")
+				err = stmt.Exec()
+				stmt.Close()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				// LastError is serialized too.
+				if err := db.LastError(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	stmt, _, err := db.Prepare(`SELECT count(*) FROM test`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnInt(0); got != 800 {
+		t.Errorf("got %d, want 800", got)
+	}
+}
+
+func TestConn_Query(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`CREATE TABLE test (id INT, name TEXT); INSERT INTO test VALUES (1, 'go'), (2, 'zig'), (3, 'rust')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	err = db.Query(`SELECT name FROM test WHERE id >= ? ORDER BY id`, []any{2}, func(stmt *sqlite3.Stmt) error {
+		names = append(names, stmt.ColumnText(0))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(names, ","); got != "zig,rust" {
+		t.Errorf("got %q, want zig,rust", got)
+	}
+
+	stop := errors.New("stop")
+	var rows int
+	err = db.Query(`SELECT * FROM test`, nil, func(stmt *sqlite3.Stmt) error {
+		rows++
+		return stop
+	})
+	if err != stop {
+		t.Errorf("got %v, want stop", err)
+	}
+	if rows != 1 {
+		t.Errorf("got %d rows, want 1", rows)
+	}
+
+	err = db.Query(`SELECT ?`, []any{struct{}{}}, func(stmt *sqlite3.Stmt) error { return nil })
+	if err == nil {
+		t.Error("want error")
+	}
+
+	// The aborted statements were finalized.
+	err = db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConn_ExecParams(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`CREATE TABLE test (id INT, name TEXT)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.ExecParams(`INSERT INTO test VALUES (?, ?)`, 1, "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := db.Changes(); got != 1 {
+		t.Errorf("got %d changes, want 1", got)
+	}
+
+	err = db.ExecParams(`INSERT INTO test VALUES (?, ?); DELETE FROM test`, 2, "zig")
+	if err == nil {
+		t.Error("want error")
+	}
+
+	err = db.ExecParams(`INSERT INTO test VALUES (?, ?)`, 1, "go", "extra")
+	if err == nil {
+		t.Error("want error")
+	}
+
+	var count int
+	err = db.Query(`SELECT count(*) FROM test`, nil, func(stmt *sqlite3.Stmt) error {
+		count = stmt.ColumnInt(0)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("got %d rows, want 1", count)
+	}
+}
+
+func TestConn_ExtendedResultCodes(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`CREATE TABLE test (col UNIQUE); INSERT INTO test VALUES (1)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.ExecParams(`INSERT INTO test VALUES (1)`)
+	var serr *sqlite3.Error
+	if !errors.As(err, &serr) {
+		t.Fatalf("got %T, want sqlite3.Error", err)
+	}
+	if rc := serr.Code(); rc != sqlite3.CONSTRAINT {
+		t.Errorf("got %d, want sqlite3.CONSTRAINT", rc)
+	}
+	if rc := serr.ExtendedCode(); rc != sqlite3.CONSTRAINT_UNIQUE {
+		t.Errorf("got %d, want sqlite3.CONSTRAINT_UNIQUE", rc)
+	}
+
+	db.ExtendedResultCodes(false)
+	err = db.ExecParams(`INSERT INTO test VALUES (1)`)
+	if !errors.As(err, &serr) {
+		t.Fatalf("got %T, want sqlite3.Error", err)
+	}
+	if rc := serr.ExtendedCode(); rc != sqlite3.ExtendedErrorCode(sqlite3.CONSTRAINT) {
+		t.Errorf("got %d, want sqlite3.CONSTRAINT", rc)
+	}
+}
+
+func TestConn_WithTimeout(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`PRAGMA busy_timeout=1234`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Already timed out.
+	err = db.WithTimeout(0, func() error {
+		return db.Exec(`SELECT 1`)
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+	if !errors.Is(err, sqlite3.INTERRUPT) {
+		t.Errorf("got %v, want sqlite3.INTERRUPT", err)
+	}
+	var serr *sqlite3.Error
+	if !errors.As(err, &serr) || serr.Code() != sqlite3.INTERRUPT {
+		t.Errorf("got %v, want an *sqlite3.Error", err)
+	}
+
+	var timeout int
+	err = db.Query(`PRAGMA busy_timeout`, nil, func(stmt *sqlite3.Stmt) error {
+		timeout = stmt.ColumnInt(0)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timeout != 1234 {
+		t.Errorf("got %d, want 1234", timeout)
+	}
+
+	err = db.WithTimeout(time.Second, func() error {
+		return db.Exec(`SELECT 1`)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConn_LastError(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.LastError(); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+
+	script := `CREATE TABLE test (col UNIQUE); INSERT INTO test VALUES (1); INSERT INTO test VALUES (1);`
+	_, err = db.ExecScript(script)
+	if err == nil {
+		t.Fatal("want error")
+	}
+	last := db.LastError()
+	if last != err {
+		t.Errorf("got %v, want %v", last, err)
+	}
+	if got := last.SQL(); got != `INSERT INTO test VALUES (1);` {
+		t.Error("got SQL: ", got)
+	}
+	if got, want := last.Offset(), strings.LastIndex(script, "INSERT"); got != want {
+		t.Errorf("got offset %d, want %d", got, want)
+	}
+
+	err = db.Exec(`SELECT 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.LastError(); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
+
+func TestConn_Exec_empty(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, sql := range []string{
+		"",
+		" \n\t",
+		";;",
+		"-- comment",
+		"/* comment */",
+		"SELECT 1; -- HERE",
+		"SELECT 1; /* HERE */ ;",
+	} {
+		if err := db.Exec(sql); err != nil {
+			t.Errorf("%q: %v", sql, err)
+		}
+	}
+}
+
+func TestConn_ClientData(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if got := db.ClientData("tenant"); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+
+	db.SetClientData("tenant", 42)
+	db.SetClientData("cache", map[string]int{})
+	if got := db.ClientData("tenant"); got != 42 {
+		t.Errorf("got %v, want 42", got)
+	}
+	if _, ok := db.ClientData("cache").(map[string]int); !ok {
+		t.Errorf("got %T, want map", db.ClientData("cache"))
+	}
+
+	db.SetClientData("tenant", nil)
+	if got := db.ClientData("tenant"); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.ClientData("cache"); got != nil {
+		t.Errorf("got %v, want nil after close", got)
+	}
+}
+
+func TestOpenImmutable(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "test.db")
+
+	db, err := sqlite3.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Exec(`CREATE TABLE test (col); INSERT INTO test VALUES ('immutable')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chmod(name, 0444); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = sqlite3.OpenImmutable(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Query(`SELECT col FROM test`, nil, func(stmt *sqlite3.Stmt) error {
+		if got := stmt.ColumnText(0); got != "immutable" {
+			t.Errorf("got %q", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Exec(`INSERT INTO test VALUES ('write')`)
+	if !errors.Is(err, sqlite3.READONLY) {
+		t.Errorf("got %v, want sqlite3.READONLY", err)
+	}
+}
+
+func TestOpenImmutable_relative(t *testing.T) {
+	t.Parallel()
+
+	dir, err := os.MkdirTemp(".", "immutable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A relative path, whose first element isn't a URI authority.
+	name := filepath.Join(dir, "test.db")
+	if filepath.IsAbs(name) {
+		t.Fatalf("got %q, want a relative path", name)
+	}
+
+	db, err := sqlite3.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Exec(`CREATE TABLE test (col); INSERT INTO test VALUES ('relative')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = sqlite3.OpenImmutable(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Query(`SELECT col FROM test`, nil, func(stmt *sqlite3.Stmt) error {
+		if got := stmt.ColumnText(0); got != "relative" {
+			t.Errorf("got %q", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConn_Begin(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "test.db")
+
+	open := func() *sqlite3.Conn {
+		db, err := sqlite3.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = db.Exec(`PRAGMA locking_mode=normal`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return db
+	}
+
+	db1 := open()
+	defer db1.Close()
+	db2 := open()
+	defer db2.Close()
+
+	err := db1.Exec(`CREATE TABLE test (col)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db1.Begin(sqlite3.TX_IMMEDIATE); err != nil {
+		t.Fatal(err)
+	}
+	if db1.GetAutocommit() {
+		t.Error("want a transaction")
+	}
+	if err := db1.Exec(`INSERT INTO test VALUES (1)`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fails at once, without a busy timeout, before doing any work.
+	err = db2.Begin(sqlite3.TX_IMMEDIATE)
+	if !errors.Is(err, sqlite3.BUSY) {
+		t.Errorf("got %v, want sqlite3.BUSY", err)
+	}
+	if !db2.GetAutocommit() {
+		t.Error("want no transaction")
+	}
+
+	if err := db1.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if !db1.GetAutocommit() {
+		t.Error("want no transaction")
+	}
+
+	if err := db2.Begin(sqlite3.TX_EXCLUSIVE); err != nil {
+		t.Fatal(err)
+	}
+	if err := db2.Exec(`INSERT INTO test VALUES (2)`); err != nil {
+		t.Fatal(err)
+	}
+	if err := db2.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db2.Begin(sqlite3.TX_DEFERRED); err != nil {
+		t.Fatal(err)
+	}
+	if err := db2.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db2.Begin(sqlite3.TxKind(10)); err == nil {
+		t.Error("want error")
+	}
+
+	stmt, _, err := db2.Prepare(`SELECT count(*) FROM test`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnInt(0); got != 1 {
+		t.Errorf("got %d, want 1", got)
+	}
+}
+
+func TestConn_SetStatementCacheSize(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	db.SetStatementCacheSize(1)
+
+	err = db.Exec(`CREATE TABLE test (a, b)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const insert = `INSERT INTO test VALUES (?, ?)`
+	if err := db.ExecParams(insert, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	// Bindings are cleared before reuse.
+	if err := db.ExecParams(insert, 3); err != nil {
+		t.Fatal(err)
+	}
+	// Errors are reported, and don't stick to the cached statement.
+	if err := db.ExecParams(`INSERT INTO nope VALUES (?)`, 1); err == nil {
+		t.Error("want error")
+	}
+	if err := db.ExecParams(insert, 5, 6); err != nil {
+		t.Fatal(err)
+	}
+
+	const query = `SELECT a, b FROM test WHERE a >= ? ORDER BY a`
+	var got []any
+	err = db.Query(query, []any{1}, func(stmt *sqlite3.Stmt) error {
+		// Nested use of the same SQL gets a different statement.
+		return db.Query(query, []any{stmt.ColumnInt(0)}, func(stmt *sqlite3.Stmt) error {
+			got = append(got, stmt.ColumnInt(0))
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 6 {
+		t.Errorf("got %d rows, want 6", len(got))
+	}
+
+	var rows []string
+	err = db.Query(query, []any{0}, func(stmt *sqlite3.Stmt) error {
+		rows = append(rows, stmt.ColumnText(0)+","+stmt.ColumnText(1))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"1,2", "3,", "5,6"}; strings.Join(rows, ";") != strings.Join(want, ";") {
+		t.Errorf("got %v, want %v", rows, want)
+	}
+
+	db.SetStatementCacheSize(0)
+	if err := db.ExecParams(insert, 7, 8); err != nil {
+		t.Fatal(err)
+	}
+
+	db.SetStatementCacheSize(16)
+	if err := db.ExecParams(insert, 9, 10); err != nil {
+		t.Fatal(err)
+	}
+	// Cached statements don't keep the connection from closing.
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkConn_ExecParams(b *testing.B) {
+	for _, size := range []int{0, 16} {
+		b.Run("cache="+strconv.Itoa(size), func(b *testing.B) {
+			db, err := sqlite3.Open(":memory:")
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer db.Close()
+
+			db.SetStatementCacheSize(size)
+
+			err = db.Exec(`CREATE TABLE test (id INTEGER PRIMARY KEY, name TEXT, value REAL)`)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				err := db.ExecParams(`INSERT OR REPLACE INTO test (id, name, value) VALUES (?, ?, ?)`,
+					i%100, "name", 1.5)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ncruces/go-sqlite3"
+	_ "github.com/ncruces/go-sqlite3/embed"
+)
+
+func TestConn_Dump(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, data BLOB, score REAL);
+		CREATE TABLE calc (a INT, b INT GENERATED ALWAYS AS (a * 2));
+		CREATE INDEX users_name ON users (name);
+		CREATE VIEW names AS SELECT name FROM users;
+		CREATE TRIGGER users_insert AFTER INSERT ON users BEGIN SELECT 1; END;
+
+		INSERT INTO users (name, data, score) VALUES
+			('it''s', x'cafe', 1),
+			('new
+line', NULL, 2.5),
+			(NULL, x'', 1e300);
+		INSERT INTO calc (a) VALUES (21);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = db.Dump(&buf, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump := buf.String()
+
+	for _, want := range []string{
+		"BEGIN TRANSACTION;\n",
+		"INSERT INTO \"users\" VALUES(1,'it''s',x'cafe',1.0);\n",
+		"INSERT INTO \"users\" VALUES(2,'new\nline',NULL,2.5);\n",
+		"INSERT INTO \"users\" VALUES(3,NULL,x'',1e+300);\n",
+		"INSERT INTO \"calc\"(\"a\") VALUES(21);\n",
+		"DELETE FROM sqlite_sequence;\n",
+		"INSERT INTO \"sqlite_sequence\" VALUES('users',3);\n",
+		"CREATE INDEX users_name ON users (name);\n",
+		"COMMIT;\n",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("dump is missing %q:\n%s", want, dump)
+		}
+	}
+
+	loaded, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer loaded.Close()
+
+	err = loaded.Load(strings.NewReader(dump))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+	err = loaded.Dump(&buf, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != dump {
+		t.Errorf("got:\n%s\nwant:\n%s", got, dump)
+	}
+
+	err = loaded.Query(`SELECT b FROM calc`, nil, func(stmt *sqlite3.Stmt) error {
+		if got := stmt.ColumnInt(0); got != 42 {
+			t.Errorf("got %d, want 42", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConn_Load_error(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Load(strings.NewReader(`
+		BEGIN;
+		CREATE TABLE test (col);
+		INSERT INTO missing VALUES (1);
+		COMMIT;
+	`))
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if !db.GetAutocommit() {
+		t.Error("want transaction rolled back")
+	}
+	if _, err := db.TableInfo("", "test"); err == nil {
+		t.Error("want table rolled back")
+	}
+}
@@ -186,3 +186,115 @@ func testIntegrity(t *testing.T, name string) {
 		t.Fatal(err)
 	}
 }
+
+func TestPool(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "test.db")
+
+	pool, err := sqlite3.NewPool(name, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	pool.Init = func(db *sqlite3.Conn) error {
+		return db.Exec(`
+			PRAGMA busy_timeout=10000;
+			PRAGMA journal_mode=truncate;
+			CREATE TABLE IF NOT EXISTS users (id INT, name VARCHAR(10));
+		`)
+	}
+
+	writer := func() error {
+		db, err := pool.Get()
+		if err != nil {
+			return err
+		}
+		defer pool.Put(db)
+
+		return db.Exec(`INSERT INTO users(id, name) VALUES(0, 'go'), (1, 'zig'), (2, 'whatever')`)
+	}
+
+	reader := func() error {
+		db, err := pool.Get()
+		if err != nil {
+			return err
+		}
+		defer pool.Put(db)
+
+		stmt, _, err := db.Prepare(`SELECT id, name FROM users`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		row := 0
+		for stmt.Step() {
+			row++
+		}
+		if row%3 != 0 {
+			t.Errorf("got %d rows, want multiple of 3", row)
+		}
+		return stmt.Err()
+	}
+
+	var group errgroup.Group
+	for i := 0; i < 100; i++ {
+		if i&7 != 7 {
+			group.Go(reader)
+		} else {
+			group.Go(writer)
+		}
+	}
+	err = group.Wait()
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = pool.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = pool.Get()
+	if err == nil {
+		t.Error("want error")
+	}
+	testIntegrity(t, name)
+}
+
+func TestPool_size(t *testing.T) {
+	_, err := sqlite3.NewPool(":memory:", 0)
+	if err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestPool_locking(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "test.db")
+
+	pool, err := sqlite3.NewPool(name, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	db1, err := pool.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Put(db1)
+	db2, err := pool.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Put(db2)
+
+	// With the default locking_mode=exclusive, db1 would keep its lock.
+	err = db1.Exec(`CREATE TABLE test (col); SELECT * FROM test;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db2.Exec(`INSERT INTO test VALUES (1)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,201 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ncruces/go-sqlite3"
+	_ "github.com/ncruces/go-sqlite3/embed"
+)
+
+func TestConn_TableInfo(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE normal (id INTEGER PRIMARY KEY, name TEXT NOT NULL DEFAULT 'x');
+		CREATE TABLE strict (id INT PRIMARY KEY, data BLOB) STRICT, WITHOUT ROWID;
+		CREATE VIEW view AS SELECT name FROM normal;
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := db.TableInfo("", "normal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &sqlite3.TableInfo{
+		Schema: "main",
+		Name:   "normal",
+		Type:   "table",
+		Columns: []sqlite3.ColumnInfo{
+			{Name: "id", Type: "INTEGER", PK: 1},
+			{Name: "name", Type: "TEXT", NotNull: true, Default: "'x'"},
+		},
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("got %+v, want %+v", info, want)
+	}
+
+	info, err = db.TableInfo("main", "strict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Strict || !info.WithoutRowID || info.Virtual() {
+		t.Errorf("got %+v", info)
+	}
+	if len(info.Columns) != 2 || info.Columns[1].Type != "BLOB" {
+		t.Errorf("got %+v", info.Columns)
+	}
+
+	info, err = db.TableInfo("", "view")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Type != "view" || info.Strict || info.WithoutRowID {
+		t.Errorf("got %+v", info)
+	}
+
+	_, err = db.TableInfo("", "missing")
+	if err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestConn_Schemas(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`ATTACH ':memory:' AS one; ATTACH ':memory:' AS two`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := db.Schemas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"main", "temp", "one", "two"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+
+	for i, want := range []string{"main", "temp", "one", "two", ""} {
+		if got := db.SchemaName(i); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+
+	err = db.Exec(`DETACH one`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := db.SchemaName(2); got != "two" {
+		t.Errorf("got %q, want two", got)
+	}
+}
+
+func TestConn_TableInfo_quoted(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	name := `it's a "weird"\name`
+	err = db.Exec(`CREATE TABLE ` + sqlite3.QuoteIdentifier(name) + ` (col)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Exec(`INSERT INTO ` + sqlite3.QuoteIdentifier(name) + ` VALUES (` + sqlite3.QuoteString(name) + `)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := db.TableInfo("main", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != name {
+		t.Errorf("got %q, want %q", info.Name, name)
+	}
+
+	err = db.Query(`SELECT col FROM `+sqlite3.QuoteIdentifier(name), nil, func(stmt *sqlite3.Stmt) error {
+		if got := stmt.ColumnText(0); got != name {
+			t.Errorf("got %q, want %q", got, name)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConn_Tables(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE normal (id INTEGER PRIMARY KEY, name TEXT);
+		CREATE VIEW view AS SELECT name FROM normal;
+		CREATE TEMP TABLE scratch (a, b, c);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string][3]string{}
+	ncol := map[string]int{}
+	collect := func(schema string) {
+		tables, err := db.Tables(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k := range got {
+			delete(got, k)
+		}
+		for _, table := range tables {
+			got[table.Name] = [3]string{table.Schema, table.Name, table.Type}
+			ncol[table.Name] = len(table.Columns)
+		}
+	}
+
+	collect("main")
+	if len(got) != 3 {
+		t.Errorf("got %v", got)
+	}
+	if got["normal"] != [3]string{"main", "normal", "table"} || ncol["normal"] != 2 {
+		t.Errorf("got %v, %d columns", got["normal"], ncol["normal"])
+	}
+	if got["view"] != [3]string{"main", "view", "view"} || ncol["view"] != 1 {
+		t.Errorf("got %v, %d columns", got["view"], ncol["view"])
+	}
+	if _, ok := got["sqlite_schema"]; !ok {
+		t.Errorf("want sqlite_schema, got %v", got)
+	}
+
+	collect("")
+	if got["scratch"] != [3]string{"temp", "scratch", "table"} || ncol["scratch"] != 3 {
+		t.Errorf("got %v, %d columns", got["scratch"], ncol["scratch"])
+	}
+	if _, ok := got["normal"]; !ok {
+		t.Errorf("want normal, got %v", got)
+	}
+}
@@ -1,9 +1,17 @@
 package tests
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"math"
+	"net"
+	"net/netip"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf16"
 
 	"github.com/ncruces/go-sqlite3"
 )
@@ -209,6 +217,9 @@ func TestStmt(t *testing.T) {
 		if got := stmt.ColumnType(0); got != sqlite3.NULL {
 			t.Errorf("got %v, want NULL", got)
 		}
+		if got := stmt.ColumnIsNull(0); got != true {
+			t.Errorf("got %v, want true", got)
+		}
 		if got := stmt.ColumnBool(0); got != false {
 			t.Errorf("got %v, want false", got)
 		}
@@ -230,6 +241,9 @@ func TestStmt(t *testing.T) {
 		if got := stmt.ColumnType(0); got != sqlite3.TEXT {
 			t.Errorf("got %v, want TEXT", got)
 		}
+		if got := stmt.ColumnIsNull(0); got != false {
+			t.Errorf("got %v, want false", got)
+		}
 		if got := stmt.ColumnBool(0); got != false {
 			t.Errorf("got %v, want false", got)
 		}
@@ -398,6 +412,10 @@ func TestStmt_BindName(t *testing.T) {
 			t.Errorf("got %d, want %d", got, id)
 		}
 	}
+
+	if got := stmt.BindNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
 }
 
 func TestStmt_ColumnTime(t *testing.T) {
@@ -461,3 +479,1159 @@ func TestStmt_ColumnTime(t *testing.T) {
 		}
 	}
 }
+
+func TestStmt_ColumnTimeAuto(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	db.SetTimeFormat(sqlite3.TimeFormatUnixMilli)
+
+	stmt, _, err := db.Prepare(`SELECT ?, typeof(?1)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	reference := time.Date(2013, 10, 7, 4, 23, 19, 120_000_000, time.UTC)
+	err = stmt.BindTimeAuto(1, reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stmt.Step() {
+		if got := stmt.ColumnInt64(0); got != reference.UnixMilli() {
+			t.Errorf("got %v, want %v", got, reference.UnixMilli())
+		}
+		if got := stmt.ColumnText(1); got != "integer" {
+			t.Errorf("got %q, want integer", got)
+		}
+		if got := stmt.ColumnTimeAuto(0); !got.Equal(reference) {
+			t.Errorf("got %v, want %v", got, reference)
+		}
+
+		var tm time.Time
+		var typ string
+		if err := stmt.Scan(&tm, &typ); err != nil {
+			t.Fatal(err)
+		}
+		if !tm.Equal(reference) {
+			t.Errorf("got %v, want %v", tm, reference)
+		}
+	}
+	if err := stmt.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStmt_Text16(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`PRAGMA encoding='UTF-16le'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`SELECT ?, length(?)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	want := utf16.Encode([]rune("Olá, 世界 🌍"))
+	err = stmt.BindText16(1, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stmt.BindText16(2, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnText16(0); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := stmt.ColumnInt(1); got != 9 {
+		t.Errorf("got %d, want 9", got)
+	}
+}
+
+func TestStmt_Scan(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, _, err := db.Prepare(`SELECT 1, 2.5, 'text', x'cafe', 1, '2013-10-07T04:23:19.12-04:00', NULL, 'null', 3`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+
+	var (
+		i    int64
+		f    float64
+		s    string
+		b    []byte
+		ok   bool
+		tm   time.Time
+		null sql.NullString
+		text sql.NullString
+		dyn  any
+	)
+	err = stmt.Scan(&i, &f, &s, &b, &ok, &tm, &null, &text, &dyn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if i != 1 {
+		t.Errorf("got %d, want 1", i)
+	}
+	if f != 2.5 {
+		t.Errorf("got %v, want 2.5", f)
+	}
+	if s != "text" {
+		t.Errorf("got %q, want text", s)
+	}
+	if string(b) != "\xca\xfe" {
+		t.Errorf("got %q, want \\xca\\xfe", b)
+	}
+	if !ok {
+		t.Errorf("got %v, want true", ok)
+	}
+	if want := time.Date(2013, 10, 7, 4, 23, 19, 120_000_000, time.FixedZone("", -4*3600)); !tm.Equal(want) {
+		t.Errorf("got %v, want %v", tm, want)
+	}
+	if null.Valid {
+		t.Errorf("got %v, want NULL", null)
+	}
+	if !text.Valid || text.String != "null" {
+		t.Errorf("got %v, want null", text)
+	}
+	if dyn != int64(3) {
+		t.Errorf("got %v, want 3", dyn)
+	}
+
+	err = stmt.Scan(&i)
+	if err == nil {
+		t.Error("want error")
+	}
+
+	var u uint8
+	err = stmt.Scan(&i, &f, &s, &b, &ok, &tm, &null, &text, &u)
+	if err == nil {
+		t.Error("want error")
+	}
+
+	// An empty BLOB is not NULL, and time text can be scanned into sql.NullTime.
+	stmt2, _, err := db.Prepare(`SELECT x'', x'', '2013-10-07T04:23:19.12-04:00', NULL`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt2.Close()
+
+	if !stmt2.Step() {
+		t.Fatal(stmt2.Err())
+	}
+
+	var ntm, nnull sql.NullTime
+	err = stmt2.Scan(&b, &dyn, &ntm, &nnull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b == nil || len(b) != 0 {
+		t.Errorf("got %#v, want []byte{}", b)
+	}
+	if got, ok := dyn.([]byte); !ok || got == nil || len(got) != 0 {
+		t.Errorf("got %#v, want []byte{}", dyn)
+	}
+	if want := time.Date(2013, 10, 7, 4, 23, 19, 120_000_000, time.FixedZone("", -4*3600)); !ntm.Valid || !ntm.Time.Equal(want) {
+		t.Errorf("got %v, want %v", ntm, want)
+	}
+	if nnull.Valid {
+		t.Errorf("got %v, want NULL", nnull)
+	}
+}
+
+func TestStmt_ScanColumn(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, _, err := db.Prepare(`SELECT 1, 1.5, 'n1' UNION ALL SELECT 2, 3.0, 'n2' UNION ALL SELECT 3, 4.5, 'n3'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	var ints []int64
+	if err := stmt.ScanColumn(0, &ints); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ints, []int64{1, 2, 3}) {
+		t.Errorf("got %v", ints)
+	}
+
+	var floats []float64
+	if err := stmt.ScanColumn(1, &floats); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(floats, []float64{1.5, 3, 4.5}) {
+		t.Errorf("got %v", floats)
+	}
+
+	var strs []string
+	if err := stmt.ScanColumn(2, &strs); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(strs, []string{"n1", "n2", "n3"}) {
+		t.Errorf("got %v", strs)
+	}
+
+	ints = ints[:0]
+	if err := stmt.ScanColumn(2, &ints); err == nil {
+		t.Error("want error")
+	}
+
+	var bools []bool
+	if err := stmt.ScanColumn(0, &bools); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestStmt_Explain(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`CREATE TABLE test (col)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`SELECT * FROM test WHERE col = 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	err = stmt.Explain(2)
+	if sqlite3.VersionNumber() < 3_043_000 {
+		// The SQLite binary is too old to support it.
+		if err == nil || err.Error() != "sqlite3: not implemented" {
+			t.Fatalf("got %v, want not implemented", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var plan []string
+	for stmt.Step() {
+		plan = append(plan, stmt.ColumnText(3))
+	}
+	if err := stmt.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 1 || !strings.HasPrefix(plan[0], "SCAN test") {
+		t.Errorf("got %q, want SCAN test", plan)
+	}
+}
+
+func TestStmt_DataCount(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`CREATE TABLE test (col)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`INSERT INTO test VALUES (1) RETURNING col, 2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	if got := stmt.ColumnCount(); got != 2 {
+		t.Errorf("got %d, want 2", got)
+	}
+	if got := stmt.DataCount(); got != 0 {
+		t.Errorf("got %d, want 0", got)
+	}
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.DataCount(); got != 2 {
+		t.Errorf("got %d, want 2", got)
+	}
+	if stmt.Step() {
+		t.Fatal("want done")
+	}
+	if got := stmt.DataCount(); got != 0 {
+		t.Errorf("got %d, want 0", got)
+	}
+	if got := stmt.ColumnCount(); got != 2 {
+		t.Errorf("got %d, want 2", got)
+	}
+}
+
+func TestStmt_BindMap(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, _, err := db.Prepare(`SELECT :a, @b, $c, :d`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	err = stmt.BindMap(map[string]any{"a": 1, "b": "two", "$c": 3.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnInt(0); got != 1 {
+		t.Errorf("got %d, want 1", got)
+	}
+	if got := stmt.ColumnText(1); got != "two" {
+		t.Errorf("got %q, want two", got)
+	}
+	if got := stmt.ColumnFloat(2); got != 3.5 {
+		t.Errorf("got %v, want 3.5", got)
+	}
+	if got := stmt.ColumnIsNull(3); !got {
+		t.Error("want NULL")
+	}
+	stmt.Reset()
+
+	err = stmt.BindMap(map[string]any{"e": 1})
+	if err == nil {
+		t.Error("want error")
+	}
+
+	ambiguous, _, err := db.Prepare(`SELECT :a, @a`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ambiguous.Close()
+
+	err = ambiguous.BindMap(map[string]any{"a": 1})
+	if err == nil {
+		t.Error("want error")
+	}
+	err = ambiguous.BindMap(map[string]any{"@a": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStmt_ColumnRawBlob(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, _, err := db.Prepare(`SELECT x'cafe' UNION ALL SELECT zeroblob(4) UNION ALL SELECT NULL`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	want := []string{"\xca\xfe", "\x00\x00\x00\x00", ""}
+	for _, want := range want {
+		if !stmt.Step() {
+			t.Fatal(stmt.Err())
+		}
+		if got := stmt.ColumnRawBlob(0); string(got) != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+	if err := stmt.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStmt_ColumnCount_schemaChange(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`CREATE TABLE test (col); INSERT INTO test VALUES (1)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`SELECT * FROM test WHERE col = ?`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	if got := stmt.BindCount(); got != 1 {
+		t.Errorf("got %d, want 1", got)
+	}
+	if got := stmt.ColumnCount(); got != 1 {
+		t.Errorf("got %d, want 1", got)
+	}
+
+	err = db.Exec(`ALTER TABLE test ADD COLUMN other`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stepping reprepares the statement.
+	stmt.BindInt(1, 1)
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnCount(); got != 2 {
+		t.Errorf("got %d, want 2", got)
+	}
+	if got := stmt.BindCount(); got != 1 {
+		t.Errorf("got %d, want 1", got)
+	}
+}
+
+func TestStmt_StrictBindings(t *testing.T) {
+	// Not parallel: sets a global.
+	defer func(old bool) { sqlite3.StrictBindings = old }(sqlite3.StrictBindings)
+	sqlite3.StrictBindings = true
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, _, err := db.Prepare(`SELECT ? UNION ALL SELECT 2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	stmt.BindInt(1, 1)
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+
+	// Binding a busy statement fails, and the run continues.
+	err = stmt.BindInt(1, 3)
+	var serr *sqlite3.Error
+	if !errors.As(err, &serr) {
+		t.Fatalf("got %T, want sqlite3.Error", err)
+	}
+	if rc := serr.Code(); rc != sqlite3.MISUSE {
+		t.Errorf("got %d, want sqlite3.MISUSE", rc)
+	}
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnInt(0); got != 2 {
+		t.Errorf("got %d, want 2", got)
+	}
+	for stmt.Step() {
+	}
+	if err := stmt.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Forgot to reset: the rebind fails, and so does the next step.
+	if err := stmt.BindInt(1, 2); err == nil {
+		t.Error("want error")
+	}
+	if stmt.Step() {
+		t.Fatal("want no row")
+	}
+	if !errors.As(stmt.Err(), &serr) {
+		t.Fatalf("got %T, want sqlite3.Error", stmt.Err())
+	}
+	if rc := serr.Code(); rc != sqlite3.MISUSE {
+		t.Errorf("got %d, want sqlite3.MISUSE", rc)
+	}
+
+	// After a reset, it works.
+	err = stmt.Reset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt.BindInt(1, 2)
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnInt(0); got != 2 {
+		t.Errorf("got %d, want 2", got)
+	}
+}
+
+func TestStmt_Uint64(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, _, err := db.Prepare(`SELECT ?, ? < 0`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	for _, want := range []uint64{0, math.MaxInt64, math.MaxInt64 + 1, math.MaxUint64} {
+		if err := stmt.BindUint64(1, want); err != nil {
+			t.Fatal(err)
+		}
+		if err := stmt.BindUint64(2, want); err != nil {
+			t.Fatal(err)
+		}
+		if stmt.Step() {
+			if got := stmt.ColumnUint64(0); got != want {
+				t.Errorf("got %d, want %d", got, want)
+			}
+			if got := stmt.ColumnBool(1); got != (want > math.MaxInt64) {
+				t.Errorf("%d: got negative %v", want, got)
+			}
+		}
+		if err := stmt.Reset(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStmt_ResetAndClear(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, _, err := db.Prepare(`SELECT ?, ?`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	if err := stmt.BindInt(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := stmt.BindText(2, "two"); err != nil {
+		t.Fatal(err)
+	}
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+
+	if err := stmt.ResetAndClear(); err != nil {
+		t.Fatal(err)
+	}
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnType(0); got != sqlite3.NULL {
+		t.Errorf("got %v, want NULL", got)
+	}
+	if got := stmt.ColumnType(1); got != sqlite3.NULL {
+		t.Errorf("got %v, want NULL", got)
+	}
+}
+
+func TestStmt_IsExplain(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		sql  string
+		want int
+	}{
+		{`SELECT 1`, 0},
+		{`EXPLAIN SELECT 1`, 1},
+		{`/* plan */ EXPLAIN QUERY PLAN SELECT 1`, 2},
+	}
+	for _, tt := range tests {
+		stmt, _, err := db.Prepare(tt.sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := stmt.IsExplain(); got != tt.want {
+			t.Errorf("%q: got %d, want %d", tt.sql, got, tt.want)
+		}
+		if err := stmt.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStmt_ColumnTextBytes(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, _, err := db.Prepare(`SELECT column1 FROM (VALUES ('abc'), (NULL), (123), ('ça'))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	var got []string
+	buf := make([]byte, 0, 64)
+	for stmt.Step() {
+		buf = stmt.ColumnTextBytes(0, buf)
+		got = append(got, string(buf))
+	}
+	if err := stmt.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"abc", "", "123", "ça"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if cap(buf) != 64 {
+		t.Errorf("buffer was reallocated")
+	}
+}
+
+func TestStmt_StepRow(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, _, err := db.Prepare(`SELECT value FROM json_each('[1, 2, 3]')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	var sum int
+	for {
+		ok, err := stmt.StepRow()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		sum += stmt.ColumnInt(0)
+	}
+	if sum != 6 {
+		t.Errorf("got %d, want 6", sum)
+	}
+
+	stmt, _, err = db.Prepare(`SELECT value FROM json_each('[1, 2, 3')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	ok, err := stmt.StepRow()
+	if ok || err == nil {
+		t.Errorf("got %v, %v, want false and an error", ok, err)
+	}
+}
+
+func TestStmt_Duration(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, _, err := db.Prepare(`SELECT ?, ?, ?`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	d := 1500*time.Millisecond + 999*time.Microsecond
+	if err := stmt.BindDuration(1, d, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := stmt.BindDuration(2, d, time.Nanosecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := stmt.BindValue(3, d); err != nil {
+		t.Fatal(err)
+	}
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+
+	if got := stmt.ColumnInt64(0); got != 1500 {
+		t.Errorf("got %d, want 1500", got)
+	}
+	if got := stmt.ColumnDuration(0, time.Millisecond); got != 1500*time.Millisecond {
+		t.Errorf("got %v, want 1.5s", got)
+	}
+	if got := stmt.ColumnInt64(1); got != int64(d) {
+		t.Errorf("got %d, want %d", got, d)
+	}
+	if got := stmt.ColumnDuration(1, time.Nanosecond); got != d {
+		t.Errorf("got %v, want %v", got, d)
+	}
+
+	var scanned time.Duration
+	var ignored int64
+	if err := stmt.Scan(&ignored, &ignored, &scanned); err != nil {
+		t.Fatal(err)
+	}
+	if scanned != d {
+		t.Errorf("got %v, want %v", scanned, d)
+	}
+}
+
+func TestStmt_LastCode(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`CREATE TABLE test (id INTEGER PRIMARY KEY)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`INSERT INTO test VALUES (1) RETURNING id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	if got := stmt.LastCode(); got != 0 {
+		t.Errorf("got %d, want 0", got)
+	}
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.LastCode(); got != 100 {
+		t.Errorf("got %d, want 100", got)
+	}
+	if stmt.Step() {
+		t.Fatal("want done")
+	}
+	if got := stmt.LastCode(); got != 101 {
+		t.Errorf("got %d, want 101", got)
+	}
+
+	if err := stmt.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if got := stmt.LastCode(); got != 0 {
+		t.Errorf("got %d, want 0", got)
+	}
+	if stmt.Step() {
+		t.Fatal("want error")
+	}
+	if got := stmt.LastCode(); got != sqlite3.CONSTRAINT_PRIMARYKEY {
+		t.Errorf("got %d, want sqlite3.CONSTRAINT_PRIMARYKEY", got)
+	}
+	if !errors.Is(stmt.Err(), sqlite3.CONSTRAINT_PRIMARYKEY) {
+		t.Errorf("got %v", stmt.Err())
+	}
+}
+
+func TestStmt_ColumnIndex(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE a (id, name);
+		CREATE TABLE b (id, a_id);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`SELECT a.id, b.id, name FROM a JOIN b ON a.id = b.a_id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	if got := stmt.ColumnName(0); got != "id" {
+		t.Errorf("got %q, want id", got)
+	}
+	if got := stmt.ColumnName(1); got != "id" {
+		t.Errorf("got %q, want id", got)
+	}
+	if i, ok := stmt.ColumnIndex("id"); i != 0 || !ok {
+		t.Errorf("got %d, %v, want 0, true", i, ok)
+	}
+	if i, ok := stmt.ColumnIndex("name"); i != 2 || !ok {
+		t.Errorf("got %d, %v, want 2, true", i, ok)
+	}
+	if i, ok := stmt.ColumnIndex("a_id"); i != -1 || ok {
+		t.Errorf("got %d, %v, want -1, false", i, ok)
+	}
+}
+
+func TestStmt_ColumnCount_reprepare(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`CREATE VIEW test AS SELECT 1 AS a`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`SELECT * FROM test`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	columns := func() []string {
+		if !stmt.Step() {
+			t.Fatal(stmt.Err())
+		}
+		names := make([]string, stmt.ColumnCount())
+		for i := range names {
+			names[i] = stmt.ColumnName(i)
+		}
+		if err := stmt.Reset(); err != nil {
+			t.Fatal(err)
+		}
+		return names
+	}
+
+	if got := columns(); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("got %q", got)
+	}
+
+	err = db.Exec(`
+		DROP VIEW test;
+		CREATE VIEW test AS SELECT 1 AS a, 2 AS b;
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := columns(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("got %q", got)
+	}
+	if i, ok := stmt.ColumnIndex("b"); i != 1 || !ok {
+		t.Errorf("got %d, %v, want 1, true", i, ok)
+	}
+}
+
+type bindStringer int
+
+func (b bindStringer) String() string { return "stringer" }
+
+type bindMarshaler struct{}
+
+func (bindMarshaler) MarshalText() ([]byte, error) { return []byte("marshaler"), nil }
+func (bindMarshaler) String() string               { return "ignored" }
+
+func TestStmt_BindValue_text(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, _, err := db.Prepare(`SELECT ?, ?`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	if err := stmt.BindValue(1, bindStringer(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := stmt.BindValue(2, bindMarshaler{}); err != nil {
+		t.Fatal(err)
+	}
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnText(0); got != "stringer" {
+		t.Errorf("got %q, want stringer", got)
+	}
+	if got := stmt.ColumnText(1); got != "marshaler" {
+		t.Errorf("got %q, want marshaler", got)
+	}
+}
+
+func BenchmarkStmt_BindBlob(b *testing.B) {
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, _, err := db.Prepare(`SELECT length(?)`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer stmt.Close()
+
+	blob := make([]byte, 64*1024)
+
+	b.SetBytes(int64(len(blob)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := stmt.BindBlob(1, blob); err != nil {
+			b.Fatal(err)
+		}
+		if err := stmt.Exec(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestStmt_Addr(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, _, err := db.Prepare(`SELECT ?, ?, ?`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	for _, s := range []string{
+		"192.0.2.1",
+		"::ffff:192.0.2.1",
+		"2001:db8::1",
+		"fe80::1%eth0",
+	} {
+		addr := netip.MustParseAddr(s)
+
+		if err := stmt.BindAddr(1, addr); err != nil {
+			t.Fatal(err)
+		}
+		if err := stmt.BindAddrBlob(2, addr); err != nil {
+			t.Fatal(err)
+		}
+		if err := stmt.BindValue(3, net.IP(addr.AsSlice())); err != nil {
+			t.Fatal(err)
+		}
+		if !stmt.Step() {
+			t.Fatal(stmt.Err())
+		}
+
+		if got := stmt.ColumnText(0); got != s {
+			t.Errorf("got %q, want %q", got, s)
+		}
+		if got := stmt.ColumnAddr(0); got != addr {
+			t.Errorf("got %v, want %v", got, addr)
+		}
+		if got := stmt.ColumnRawBlob(1); len(got) != 16 {
+			t.Errorf("got %d bytes, want 16", len(got))
+		}
+		if got := stmt.ColumnAddr(1); got != netip.AddrFrom16(addr.As16()) {
+			t.Errorf("got %v, want %v", got, addr)
+		}
+		// net.IP has no zones, and formats IPv4-mapped addresses as IPv4.
+		if want := addr.WithZone("").Unmap(); stmt.ColumnAddr(2) != want {
+			t.Errorf("got %v, want %v", stmt.ColumnAddr(2), want)
+		}
+
+		var scanned netip.Addr
+		var ignored any
+		if err := stmt.Scan(&scanned, &ignored, &ignored); err != nil {
+			t.Fatal(err)
+		}
+		if scanned != addr {
+			t.Errorf("got %v, want %v", scanned, addr)
+		}
+		if err := stmt.Reset(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := stmt.BindAddr(1, netip.Addr{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := stmt.BindText(2, "invalid"); err != nil {
+		t.Fatal(err)
+	}
+	if err := stmt.BindValue(3, net.IP(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnType(0); got != sqlite3.NULL {
+		t.Errorf("got %v, want NULL", got)
+	}
+	if got := stmt.ColumnAddr(0); got.IsValid() {
+		t.Errorf("got %v, want zero Addr", got)
+	}
+	if got := stmt.ColumnType(2); got != sqlite3.NULL {
+		t.Errorf("got %v, want NULL", got)
+	}
+	if stmt.ColumnAddr(1); stmt.Err() == nil {
+		t.Error("want error")
+	}
+}
+
+func TestStmt_StepContext(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, _, err := db.Prepare(`SELECT 1 UNION ALL SELECT 2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for i := 1; i <= 2; i++ {
+		row, err := stmt.StepContext(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !row {
+			t.Fatal("want a row")
+		}
+		if got := stmt.ColumnInt(0); got != i {
+			t.Errorf("got %d, want %d", got, i)
+		}
+		// The interrupt context is kept between rows.
+		if old := db.SetInterrupt(ctx); old != ctx {
+			t.Errorf("got %v, want ctx", old)
+		}
+	}
+	row, err := stmt.StepContext(ctx)
+	if row || err != nil {
+		t.Errorf("got %v, %v; want no row, no error", row, err)
+	}
+	// The interrupt context is restored when done.
+	if old := db.SetInterrupt(nil); old == ctx {
+		t.Error("want the interrupt context restored")
+	}
+
+	cancel()
+	if err := stmt.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	row, err = stmt.StepContext(ctx)
+	if row {
+		t.Error("want no row")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+	if !errors.Is(err, sqlite3.INTERRUPT) {
+		t.Errorf("got %v, want sqlite3.INTERRUPT", err)
+	}
+	var serr *sqlite3.Error
+	if !errors.As(err, &serr) || serr.Code() != sqlite3.INTERRUPT {
+		t.Errorf("got %v, want an *sqlite3.Error", err)
+	}
+	if !strings.Contains(err.Error(), "interrupted") {
+		t.Errorf("got %v, want an interrupt", err)
+	}
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Unix(0, 0))
+	defer cancel()
+	stmt.Reset()
+	_, err = stmt.StepContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+
+	// The interrupt context is restored.
+	stmt.Reset()
+	row, err = stmt.StepContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !row {
+		t.Error("want a row")
+	}
+}
@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ncruces/go-sqlite3"
+	_ "github.com/ncruces/go-sqlite3/embed"
+)
+
+func TestVersion(t *testing.T) {
+	t.Parallel()
+
+	version := sqlite3.Version()
+	if !strings.HasPrefix(version, "3.") {
+		t.Errorf("got %q, want 3.x.y", version)
+	}
+
+	var want int
+	for _, p := range strings.Split(version, ".") {
+		n, _ := strconv.Atoi(p)
+		want = want*1000 + n
+	}
+	if got := sqlite3.VersionNumber(); got != want || got < 3_035_000 {
+		t.Errorf("got %d, want %d", got, want)
+	}
+
+	if id := sqlite3.SourceID(); len(id) < 20 {
+		t.Errorf("got %q, want a source id", id)
+	}
+}
+
+func TestCompileOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := sqlite3.CompileOptions()
+	if len(opts) == 0 {
+		t.Fatal("got no options")
+	}
+	for _, opt := range opts {
+		if strings.HasPrefix(opt, "SQLITE_") {
+			t.Errorf("got %q, want no prefix", opt)
+		}
+	}
+
+	if !sqlite3.CompileOptionUsed("THREADSAFE") {
+		t.Error("want THREADSAFE")
+	}
+	if !sqlite3.CompileOptionUsed("sqlite_omit_load_extension") {
+		t.Error("want OMIT_LOAD_EXTENSION")
+	}
+	if sqlite3.CompileOptionUsed("THREAD") {
+		t.Error("want no THREAD")
+	}
+	if sqlite3.CompileOptionUsed("ENABLE_NONEXISTENT") {
+		t.Error("want no ENABLE_NONEXISTENT")
+	}
+}
@@ -0,0 +1,138 @@
+package sqlite3
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var library struct {
+	mtx  sync.Mutex
+	info *libraryInfo
+}
+
+type libraryInfo struct {
+	version  string
+	number   int
+	sourceID string
+	options  []string
+}
+
+// loadLibrary queries the SQLite library for its version
+// and compile-time options, using a temporary in-memory database.
+// The embedded SQLite binary doesn't export sqlite3_libversion,
+// sqlite3_libversion_number, sqlite3_sourceid or sqlite3_compileoption_get,
+// so this can't call them directly, and needs a connection.
+// The result is cached if it succeeds; if it fails,
+// loadLibrary returns the zero libraryInfo and the error,
+// and tries again on the next call.
+func loadLibrary() (*libraryInfo, error) {
+	library.mtx.Lock()
+	defer library.mtx.Unlock()
+
+	if library.info != nil {
+		return library.info, nil
+	}
+	info, err := queryLibrary()
+	if err != nil {
+		return &libraryInfo{}, err
+	}
+	library.info = info
+	return info, nil
+}
+
+func queryLibrary() (*libraryInfo, error) {
+	c, err := Open(":memory:")
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	var info libraryInfo
+	err = c.Query(`SELECT sqlite_version(), sqlite_source_id()`, nil, func(stmt *Stmt) error {
+		info.version = stmt.ColumnText(0)
+		info.sourceID = stmt.ColumnText(1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// X.Y.Z is encoded as X*1000000 + Y*1000 + Z.
+	for _, p := range strings.SplitN(info.version, ".", 3) {
+		n, _ := strconv.Atoi(p)
+		info.number = info.number*1000 + n
+	}
+
+	err = c.Query(`PRAGMA compile_options`, nil, func(stmt *Stmt) error {
+		info.options = append(info.options, stmt.ColumnText(0))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Version returns the version of the SQLite library, as "X.Y.Z".
+// It returns an empty string if the SQLite binary can't be loaded.
+//
+// The embedded SQLite binary doesn't export sqlite3_libversion,
+// so the first call to Version, or to the other functions
+// that describe the library, opens a temporary in-memory connection.
+// The result is cached once that succeeds.
+//
+// https://www.sqlite.org/c3ref/libversion.html
+func Version() string {
+	lib, _ := loadLibrary()
+	return lib.version
+}
+
+// VersionNumber returns the version of the SQLite library,
+// as an integer with the value X*1000000 + Y*1000 + Z.
+// It returns 0 if the SQLite binary can't be loaded.
+//
+// https://www.sqlite.org/c3ref/libversion.html
+func VersionNumber() int {
+	lib, _ := loadLibrary()
+	return lib.number
+}
+
+// SourceID returns the check-in identifier of the SQLite library source code.
+// It returns an empty string if the SQLite binary can't be loaded.
+//
+// https://www.sqlite.org/c3ref/libversion.html
+func SourceID() string {
+	lib, _ := loadLibrary()
+	return lib.sourceID
+}
+
+// CompileOptions returns the compile-time options used to build the SQLite library,
+// without the SQLITE_ prefix (e.g. "THREADSAFE=0").
+// It returns nil if the SQLite binary can't be loaded.
+//
+// https://www.sqlite.org/c3ref/compileoption_get.html
+func CompileOptions() []string {
+	lib, _ := loadLibrary()
+	return append([]string(nil), lib.options...)
+}
+
+// CompileOptionUsed reports whether the option was defined
+// when the SQLite library was built.
+// The SQLITE_ prefix may be omitted, and the comparison is case insensitive.
+//
+// https://www.sqlite.org/c3ref/compileoption_get.html
+func CompileOptionUsed(name string) bool {
+	lib, _ := loadLibrary()
+	if len(name) > 7 && strings.EqualFold(name[:7], "SQLITE_") {
+		name = name[7:]
+	}
+	for _, opt := range lib.options {
+		// Options are matched by name, ignoring any value.
+		if len(opt) >= len(name) && strings.EqualFold(opt[:len(name)], name) &&
+			(len(opt) == len(name) || opt[len(name)] == '=') {
+			return true
+		}
+	}
+	return false
+}
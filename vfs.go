@@ -86,10 +86,20 @@ func vfsLocaltime(ctx context.Context, mod api.Module, t uint64, pTm uint32) uin
 
 func vfsRandomness(ctx context.Context, mod api.Module, pVfs, nByte, zByte uint32) uint32 {
 	mem := memory{mod}.view(zByte, nByte)
-	n, _ := rand.Reader.Read(mem)
+	n, _ := io.ReadFull(vfsRandSource(ctx), mem)
 	return uint32(n)
 }
 
+type randSourceKey struct{}
+
+// vfsRandSource returns the [RandSource] captured in ctx by [OpenFlags].
+func vfsRandSource(ctx context.Context) io.Reader {
+	if r, ok := ctx.Value(randSourceKey{}).(io.Reader); ok {
+		return r
+	}
+	return rand.Reader
+}
+
 func vfsSleep(ctx context.Context, pVfs, nMicro uint32) uint32 {
 	time.Sleep(time.Duration(nMicro) * time.Microsecond)
 	return _OK
@@ -212,19 +222,25 @@ func vfsOpen(ctx context.Context, mod api.Module, pVfs, zName, pFile uint32, fla
 	}
 
 	var err error
-	var file *os.File
+	var file vfsFile
 	if zName == 0 {
-		file, err = os.CreateTemp("", "*.db")
+		file, err = os.CreateTemp(TempDir, "*.db")
 	} else {
 		name := memory{mod}.readString(zName, _MAX_PATHNAME)
-		file, err = os.OpenFile(name, oflags, 0600)
+		if fsys, fname, ok := vfsLookupFS(name); ok {
+			file, err = vfsOpenFSFile(fsys, fname)
+		} else {
+			file, err = os.OpenFile(name, oflags, 0600)
+		}
 	}
 	if err != nil {
 		return uint32(CANTOPEN)
 	}
 
 	if flags&OPEN_DELETEONCLOSE != 0 {
-		vfsOS.DeleteOnClose(file)
+		if f, ok := file.(*os.File); ok {
+			vfsOS.DeleteOnClose(f)
+		}
 	}
 
 	id := vfsGetFileID(file)
@@ -248,7 +264,7 @@ func vfsClose(ctx context.Context, mod api.Module, pFile uint32) uint32 {
 func vfsRead(ctx context.Context, mod api.Module, pFile, zBuf, iAmt uint32, iOfst uint64) uint32 {
 	buf := memory{mod}.view(zBuf, iAmt)
 
-	file := vfsFilePtr{mod, pFile}.OSFile()
+	file := vfsFilePtr{mod, pFile}.File()
 	n, err := file.ReadAt(buf, int64(iOfst))
 	if n == int(iAmt) {
 		return _OK
@@ -265,7 +281,7 @@ func vfsRead(ctx context.Context, mod api.Module, pFile, zBuf, iAmt uint32, iOfs
 func vfsWrite(ctx context.Context, mod api.Module, pFile, zBuf, iAmt uint32, iOfst uint64) uint32 {
 	buf := memory{mod}.view(zBuf, iAmt)
 
-	file := vfsFilePtr{mod, pFile}.OSFile()
+	file := vfsFilePtr{mod, pFile}.File()
 	_, err := file.WriteAt(buf, int64(iOfst))
 	if err != nil {
 		return uint32(IOERR_WRITE)
@@ -274,7 +290,7 @@ func vfsWrite(ctx context.Context, mod api.Module, pFile, zBuf, iAmt uint32, iOf
 }
 
 func vfsTruncate(ctx context.Context, mod api.Module, pFile uint32, nByte uint64) uint32 {
-	file := vfsFilePtr{mod, pFile}.OSFile()
+	file := vfsFilePtr{mod, pFile}.File()
 	err := file.Truncate(int64(nByte))
 	if err != nil {
 		return uint32(IOERR_TRUNCATE)
@@ -283,7 +299,7 @@ func vfsTruncate(ctx context.Context, mod api.Module, pFile uint32, nByte uint64
 }
 
 func vfsSync(ctx context.Context, mod api.Module, pFile, flags uint32) uint32 {
-	file := vfsFilePtr{mod, pFile}.OSFile()
+	file := vfsFilePtr{mod, pFile}.File()
 	err := file.Sync()
 	if err != nil {
 		return uint32(IOERR_FSYNC)
@@ -292,10 +308,10 @@ func vfsSync(ctx context.Context, mod api.Module, pFile, flags uint32) uint32 {
 }
 
 func vfsFileSize(ctx context.Context, mod api.Module, pFile, pSize uint32) uint32 {
-	// This uses [os.File.Seek] because we don't care about the offset for reading/writing.
+	// This uses [io.Seeker.Seek] because we don't care about the offset for reading/writing.
 	// But consider using [os.File.Stat] instead (as other VFSes do).
 
-	file := vfsFilePtr{mod, pFile}.OSFile()
+	file := vfsFilePtr{mod, pFile}.File()
 	off, err := file.Seek(0, io.SeekEnd)
 	if err != nil {
 		return uint32(IOERR_SEEK)
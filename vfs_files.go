@@ -1,18 +1,29 @@
 package sqlite3
 
 import (
+	"io"
 	"os"
 	"sync"
 
 	"github.com/tetratelabs/wazero/api"
 )
 
+// A vfsFile is implemented by *os.File, and by read-only files from an [fs.FS].
+type vfsFile interface {
+	io.ReaderAt
+	io.WriterAt
+	io.Seeker
+	io.Closer
+	Truncate(size int64) error
+	Sync() error
+}
+
 var (
-	vfsOpenFiles    []*os.File
+	vfsOpenFiles    []vfsFile
 	vfsOpenFilesMtx sync.Mutex
 )
 
-func vfsGetFileID(file *os.File) uint32 {
+func vfsGetFileID(file vfsFile) uint32 {
 	vfsOpenFilesMtx.Lock()
 	defer vfsOpenFilesMtx.Unlock()
 
@@ -43,13 +54,19 @@ type vfsFilePtr struct {
 	ptr uint32
 }
 
-func (p vfsFilePtr) OSFile() *os.File {
+func (p vfsFilePtr) File() vfsFile {
 	id := p.ID()
 	vfsOpenFilesMtx.Lock()
 	defer vfsOpenFilesMtx.Unlock()
 	return vfsOpenFiles[id]
 }
 
+// OSFile returns the *os.File, or nil for files from an [fs.FS].
+func (p vfsFilePtr) OSFile() *os.File {
+	f, _ := p.File().(*os.File)
+	return f
+}
+
 func (p vfsFilePtr) ID() uint32 {
 	return memory{p}.readUint32(p.ptr + ptrlen)
 }
@@ -0,0 +1,107 @@
+package sqlite3
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+type vfsFSEntry struct {
+	fsys fs.FS
+	name string
+}
+
+var (
+	vfsFSNames = map[string]vfsFSEntry{}
+	vfsFSMtx   sync.Mutex
+	vfsFSCount uint64
+)
+
+// vfsRegisterFS makes the file name from fsys available to vfsOpen
+// under a unique, absolute, path.
+// Call unregister once SQLite has opened the file.
+func vfsRegisterFS(fsys fs.FS, name string) (path string, unregister func()) {
+	vfsFSMtx.Lock()
+	defer vfsFSMtx.Unlock()
+
+	vfsFSCount++
+	dir := string(filepath.Separator) + "sqlite3-fs-" + strconv.FormatUint(vfsFSCount, 10)
+	path, err := filepath.Abs(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		panic(err)
+	}
+
+	vfsFSNames[path] = vfsFSEntry{fsys, name}
+	return path, func() {
+		vfsFSMtx.Lock()
+		defer vfsFSMtx.Unlock()
+		delete(vfsFSNames, path)
+	}
+}
+
+func vfsLookupFS(path string) (fsys fs.FS, name string, ok bool) {
+	vfsFSMtx.Lock()
+	defer vfsFSMtx.Unlock()
+	e, ok := vfsFSNames[path]
+	return e.fsys, e.name, ok
+}
+
+func vfsOpenFSFile(fsys fs.FS, name string) (vfsFile, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	var r io.ReaderAt
+	switch f := f.(type) {
+	case io.ReaderAt:
+		r = f
+	case io.ReadSeeker:
+		r = &vfsSeekerReaderAt{r: f}
+	default:
+		f.Close()
+		return nil, errors.New("sqlite3: file does not implement io.ReaderAt or io.Seeker")
+	}
+
+	return vfsFSFile{io.NewSectionReader(r, 0, fi.Size()), f}, nil
+}
+
+// vfsSeekerReaderAt adapts an [io.ReadSeeker] to an [io.ReaderAt],
+// for files that don't implement it.
+type vfsSeekerReaderAt struct {
+	mtx sync.Mutex
+	r   io.ReadSeeker
+}
+
+func (s *vfsSeekerReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, err := s.r.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(s.r, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// vfsFSFile adapts a read-only [fs.File] to a vfsFile.
+type vfsFSFile struct {
+	*io.SectionReader
+	io.Closer
+}
+
+func (vfsFSFile) WriteAt(p []byte, off int64) (int, error) { return 0, fs.ErrPermission }
+func (vfsFSFile) Truncate(size int64) error                { return fs.ErrPermission }
+func (vfsFSFile) Sync() error                              { return nil }
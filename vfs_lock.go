@@ -82,6 +82,12 @@ func vfsLock(ctx context.Context, mod api.Module, pFile uint32, eLock vfsLockSta
 		return _OK
 	}
 
+	// Files from an fs.FS are read-only, and can't be locked.
+	if file == nil {
+		ptr.SetLock(eLock)
+		return _OK
+	}
+
 	switch eLock {
 	case _SHARED_LOCK:
 		// Must be unlocked to get SHARED.
@@ -152,6 +158,12 @@ func vfsUnlock(ctx context.Context, mod api.Module, pFile uint32, eLock vfsLockS
 		return _OK
 	}
 
+	// Files from an fs.FS are read-only, and can't be locked.
+	if file == nil {
+		ptr.SetLock(eLock)
+		return _OK
+	}
+
 	switch eLock {
 	case _SHARED_LOCK:
 		if rc := vfsOS.DowngradeLock(file, cLock); rc != _OK {
@@ -180,7 +192,12 @@ func vfsCheckReservedLock(ctx context.Context, mod api.Module, pFile, pResOut ui
 
 	file := ptr.OSFile()
 
-	locked, rc := vfsOS.CheckReservedLock(file)
+	// Files from an fs.FS are read-only, and can't be locked.
+	var locked bool
+	var rc xErrorCode
+	if file != nil {
+		locked, rc = vfsOS.CheckReservedLock(file)
+	}
 	var res uint32
 	if locked {
 		res = 1
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"testing"
+	"testing/fstest"
 )
 
 func Test_vfsLock(t *testing.T) {
@@ -124,3 +125,44 @@ func Test_vfsLock(t *testing.T) {
 		t.Fatal("returned", rc)
 	}
 }
+
+func Test_vfsLock_fs(t *testing.T) {
+	fsys := fstest.MapFS{"test.db": &fstest.MapFile{Data: []byte("data")}}
+	file, err := vfsOpenFSFile(fsys, "test.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	const (
+		pFile   = 4
+		pOutput = 32
+	)
+	mem := newMemory(128)
+	vfsFilePtr{mem.mod, pFile}.SetID(vfsGetFileID(file)).SetLock(_NO_LOCK)
+
+	// Files from an fs.FS can't be locked, but locking them must not fail.
+	rc := vfsLock(context.TODO(), mem.mod, pFile, _SHARED_LOCK)
+	if rc != _OK {
+		t.Fatal("returned", rc)
+	}
+	rc = vfsLock(context.TODO(), mem.mod, pFile, _EXCLUSIVE_LOCK)
+	if rc != _OK {
+		t.Fatal("returned", rc)
+	}
+	if got := (vfsFilePtr{mem.mod, pFile}).Lock(); got != _EXCLUSIVE_LOCK {
+		t.Errorf("got %d, want _EXCLUSIVE_LOCK", got)
+	}
+	rc = vfsUnlock(context.TODO(), mem.mod, pFile, _NO_LOCK)
+	if rc != _OK {
+		t.Fatal("returned", rc)
+	}
+
+	rc = vfsCheckReservedLock(context.TODO(), mem.mod, pFile, pOutput)
+	if rc != _OK {
+		t.Fatal("returned", rc)
+	}
+	if got := mem.readUint32(pOutput); got != 0 {
+		t.Error("file was locked")
+	}
+}
@@ -269,3 +269,24 @@ func Test_vfsFile(t *testing.T) {
 		t.Fatal("returned", rc)
 	}
 }
+
+func Test_vfsOpen_tempDir(t *testing.T) {
+	dir := t.TempDir()
+	defer func(old string) { TempDir = old }(TempDir)
+	TempDir = dir
+
+	mem := newMemory(128)
+	rc := vfsOpen(context.TODO(), mem.mod, 0, 0, 4, OPEN_CREATE|OPEN_EXCLUSIVE|OPEN_READWRITE, 0)
+	if rc != _OK {
+		t.Fatal("returned", rc)
+	}
+	defer vfsClose(context.TODO(), mem.mod, 4)
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("got %d files, want 1", len(files))
+	}
+}